@@ -0,0 +1,57 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// muxurl 根据配置文件中命名的路由项生成类型安全的地址构建函数。
+//
+// 一般配合 go generate 使用：
+//  //go:generate muxurl -config=./routes.yaml -pkg=routes -o=./urls.go
+//
+// 配置文件的格式可参考 github.com/issue9/mux/config，
+// 生成的代码可参考 github.com/issue9/mux/urlgen。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/issue9/mux/urlgen"
+)
+
+func main() {
+	conf := flag.String("config", "", "路由项的配置文件，支持 JSON 和 YAML 格式")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "生成代码的包名，默认为 go generate 所在的包")
+	output := flag.String("o", "", "输出的文件，为空表示输出到标准输出")
+	flag.Parse()
+
+	if *conf == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*conf, *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(conf, pkg, output string) error {
+	routes, err := urlgen.FromConfig(conf)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	if err = urlgen.Generate(buf, pkg, routes); err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(output, buf.Bytes(), 0644)
+}
@@ -0,0 +1,256 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package config 从配置文件中加载路由项。
+//
+// 配置文件可以是 JSON 或是 YAML 格式，每一条路由项通过 handler
+// 字段指定其在 Registry 中的处理函数：
+//  routes:
+//    - pattern: /posts/{id:\d+}
+//      name: post
+//      handler: post
+//      methods: [GET, DELETE]
+//    - pattern: /posts
+//      handler: posts
+//
+// 对应的 JSON 格式如下：
+//  {
+//    "routes": [
+//      {"pattern": "/posts/{id:\\d+}", "name": "post", "handler": "post", "methods": ["GET", "DELETE"]},
+//      {"pattern": "/posts", "handler": "posts"}
+//    ]
+//  }
+//
+// methods 为空表示除 OPTIONS 之外的所有请求方法，与 Mux.Any() 相同。
+//
+// 加载：
+//  registry := config.Registry{"post": postHandler, "posts": postsHandler}
+//  err := config.Load(m, "./routes.yaml", registry) // m 可以是 *mux.Mux 或是 *mux.Prefix
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/issue9/mux"
+	"github.com/issue9/mux/internal/handlers"
+	"github.com/issue9/mux/internal/tree"
+)
+
+// Router 可以注册路由项的对象，*mux.Mux 和 *mux.Prefix 均实现了该接口。
+//
+// Names 应该返回所有已经使用的名称，Register 通过它检测名称是否冲突。
+type Router interface {
+	Handle(pattern string, h http.Handler, methods ...string) error
+	Name(name, pattern string) error
+	Names() map[string]string
+	Route(pattern string) *mux.Route
+}
+
+// Registry 处理函数的注册表，键名与配置文件中的 handler 字段相对应。
+type Registry map[string]http.Handler
+
+// Config 表示配置文件的内容
+type Config struct {
+	Routes []*Route `json:"routes"`
+}
+
+// Route 表示配置文件中的一条路由项
+type Route struct {
+	Pattern string   `json:"pattern"`
+	Name    string   `json:"name,omitempty"`
+	Handler string   `json:"handler"`
+	Methods []string `json:"methods,omitempty"`
+
+	line int // 在配置文件中的行号
+}
+
+// Line 该路由项在配置文件中的行号，从 1 开始，非从配置文件中加载的为 0。
+func (r *Route) Line() int {
+	return r.line
+}
+
+// Load 从文件 path 中加载路由项，并注册到 r 中。
+//
+// 文件格式可参考 ParseFile。
+func Load(r Router, path string, registry Registry) error {
+	conf, err := ParseFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = conf.Register(r, registry)
+	setFile(err, path)
+	return err
+}
+
+// ParseFile 解析文件 path 中的内容。
+//
+// 根据扩展名决定文件的格式，.json 为 JSON 格式，.yaml 和 .yml 为 YAML 格式。
+func ParseFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf *Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		conf, err = ParseJSON(data)
+	case ".yaml", ".yml":
+		conf, err = ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("不支持的文件格式 %s", path)
+	}
+
+	if err != nil {
+		setFile(err, path)
+		return nil, err
+	}
+	return conf, nil
+}
+
+// 为错误信息指定文件名
+func setFile(err error, path string) {
+	if errs, ok := err.(Errors); ok {
+		for _, e := range errs {
+			e.File = path
+		}
+	}
+}
+
+// Register 将 conf 中的路由项注册到 r 中。
+//
+// 在注册之前会检测所有的路由项，包括是否与 r 中已有的路由项或是名称冲突，
+// 只要有一条不合法，就不会注册任何路由项，
+// 返回的错误为 Errors 类型，包含了所有的错误信息。
+func (conf *Config) Register(r Router, registry Registry) error {
+	errs := conf.validate(registry)
+	if len(errs) == 0 { // 格式正确之后，才检测与已有路由项是否冲突
+		errs = conf.check(r)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, route := range conf.Routes {
+		// 已经检测过，除非同时有其它地方在修改 r，否则不会出错。
+		if err := r.Handle(route.Pattern, registry[route.Handler], route.Methods...); err != nil {
+			return Errors{newError(route.line, err.Error())}
+		}
+
+		if route.Name != "" {
+			if err := r.Name(route.Name, route.Pattern); err != nil {
+				return Errors{newError(route.line, err.Error())}
+			}
+		}
+	}
+
+	return nil
+}
+
+// 检测所有路由项是否与 r 中已有的路由项或是名称冲突
+func (conf *Config) check(r Router) Errors {
+	var errs Errors
+	names := r.Names()
+
+	for _, route := range conf.Routes {
+		if exists := r.Route(route.Pattern); exists != nil {
+			ms := route.Methods
+			if len(ms) == 0 {
+				ms = anyMethods()
+			}
+
+			for _, m := range ms {
+				// OPTIONS 可以覆盖自动生成的处理函数，交由 Handle 判断
+				if m != http.MethodOptions && hasMethod(exists.Methods(), m) {
+					errs = append(errs, newError(route.line, fmt.Sprintf("%s %s 已经存在", m, route.Pattern)))
+				}
+			}
+		}
+
+		if route.Name == "" {
+			continue
+		}
+		if _, found := names[route.Name]; found {
+			errs = append(errs, newError(route.line, fmt.Sprintf("name %s 已经存在", route.Name)))
+		}
+	}
+
+	return errs
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// 检测所有路由项的合法性
+func (conf *Config) validate(registry Registry) Errors {
+	var errs Errors
+	names := make(map[string]int, len(conf.Routes))     // 名称与其所在的行号
+	methods := make(map[string]int, len(conf.Routes)*2) // 匹配模式加请求方法与其所在的行号
+
+	for _, route := range conf.Routes {
+		if route.Pattern == "" {
+			errs = append(errs, newError(route.line, "pattern 不能为空"))
+		} else if _, err := tree.Parse(route.Pattern); err != nil {
+			errs = append(errs, newError(route.line, fmt.Sprintf("pattern %s 格式错误：%s", route.Pattern, err.Error())))
+		}
+
+		if route.Handler == "" {
+			errs = append(errs, newError(route.line, "handler 不能为空"))
+		} else if registry[route.Handler] == nil {
+			errs = append(errs, newError(route.line, fmt.Sprintf("未注册的 handler %s", route.Handler)))
+		}
+
+		ms := route.Methods
+		if len(ms) == 0 {
+			ms = anyMethods()
+		}
+		for _, m := range ms {
+			if !handlers.IsSupported(m) {
+				errs = append(errs, newError(route.line, fmt.Sprintf("不支持的请求方法 %s", m)))
+				continue
+			}
+
+			key := m + " " + route.Pattern
+			if line, found := methods[key]; found {
+				errs = append(errs, newError(route.line, fmt.Sprintf("%s 与第 %d 行重复", key, line)))
+				continue
+			}
+			methods[key] = route.line
+		}
+
+		if route.Name == "" {
+			continue
+		}
+		if line, found := names[route.Name]; found {
+			errs = append(errs, newError(route.line, fmt.Sprintf("name %s 与第 %d 行重复", route.Name, line)))
+			continue
+		}
+		names[route.Name] = route.line
+	}
+
+	return errs
+}
+
+// 除 OPTIONS 之外的所有请求方法，与 methods 为空时 Handle 的行为相同。
+func anyMethods() []string {
+	ms := handlers.Methods()
+	ret := ms[:0]
+	for _, m := range ms {
+		if m != http.MethodOptions {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
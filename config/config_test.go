@@ -0,0 +1,209 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+)
+
+func buildHandler(code int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	})
+}
+
+var registry = Registry{
+	"post":  buildHandler(201),
+	"posts": buildHandler(202),
+	"user":  buildHandler(203),
+}
+
+func TestLoad(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(path string, prefix bool) {
+		m := mux.New(false, false, nil, nil)
+		base := ""
+		if prefix {
+			base = "/api"
+			a.NotError(Load(m.Prefix(base), path, registry))
+		} else {
+			a.NotError(Load(m, path, registry))
+		}
+
+		serve := func(method, path string, code int) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(method, base+path, nil)
+			m.ServeHTTP(w, r)
+			a.Equal(w.Code, code, "%s %s 的状态码 %d", method, path, w.Code)
+		}
+
+		serve(http.MethodGet, "/posts/1", 201)
+		serve(http.MethodDelete, "/posts/1", 201)
+		serve(http.MethodPost, "/posts/1", http.StatusMethodNotAllowed)
+		serve(http.MethodGet, "/posts", 202)
+		serve(http.MethodPost, "/posts", 202)
+		serve(http.MethodPatch, "/users/abc", 203)
+
+		url, err := m.URL("post", map[string]string{"id": "5"})
+		a.NotError(err).Equal(url, base+"/posts/5")
+	}
+
+	test("./testdata/routes.yaml", false)
+	test("./testdata/routes.json", false)
+	test("./testdata/routes.yaml", true)
+	test("./testdata/routes.json", true)
+
+	// 文件格式错误
+	m := mux.New(false, false, nil, nil)
+	a.Error(Load(m, "./testdata/routes.xml", registry))
+	a.Error(Load(m, "./testdata/not-exists.json", registry))
+
+	// 不合法的路由项
+	err := Load(m, "./testdata/invalid.json", registry)
+	errs, ok := err.(Errors)
+	a.True(ok).Equal(len(errs), 4)
+	a.Equal(errs[0].File, "./testdata/invalid.json").Equal(errs[0].Line, 3)
+	a.Equal(errs[1].Line, 4).True(strings.Contains(errs[1].Message, "not-exists"))
+	a.Equal(errs[2].Line, 4).True(strings.Contains(errs[2].Message, "FOO"))
+	a.Equal(errs[3].Line, 6).True(strings.Contains(errs[3].Message, "第 5 行"))
+	a.True(strings.HasPrefix(err.Error(), "./testdata/invalid.json 第 3 行："))
+	a.Equal(len(m.Routes()), 0) // 有错误时，不会注册任何路由项
+
+	err = Load(m, "./testdata/invalid.yaml", registry)
+	errs, ok = err.(Errors)
+	a.True(ok).Equal(len(errs), 4)
+	a.Equal(errs[0].File, "./testdata/invalid.yaml").Equal(errs[0].Line, 2)
+	a.Equal(errs[1].Line, 4).True(strings.Contains(errs[1].Message, "not-exists"))
+	a.Equal(errs[2].Line, 4).True(strings.Contains(errs[2].Message, "FOO"))
+	a.Equal(errs[3].Line, 10).True(strings.Contains(errs[3].Message, "第 7 行"))
+	a.True(strings.HasPrefix(err.Error(), "./testdata/invalid.yaml 第 2 行："))
+	a.Equal(len(m.Routes()), 0)
+}
+
+func TestConfig_Register(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts", buildHandler(200))
+
+	conf, err := ParseJSON([]byte(`{"routes": [
+		{"pattern": "/users", "handler": "user"},
+		{"pattern": "/posts", "handler": "posts", "methods": ["GET"]}
+	]}`))
+	a.NotError(err)
+	err = conf.Register(m, registry)
+	a.Error(err)
+	errs, ok := err.(Errors)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Line, 3)
+	a.Equal(len(m.Routes()), 1) // 与已有路由项冲突时，不会注册任何路由项
+
+	// 与已有的名称冲突
+	a.NotError(m.Name("user", "/posts"))
+	conf, err = ParseJSON([]byte(`{"routes": [
+		{"pattern": "/users", "handler": "user"},
+		{"pattern": "/users/{id}", "handler": "user", "name": "user"}
+	]}`))
+	a.NotError(err)
+	errs, ok = conf.Register(m.Prefix("/api"), registry).(Errors)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Line, 3)
+	a.Equal(len(m.Routes()), 1)
+
+	// 重复的路由项
+	conf, err = ParseJSON([]byte(`{"routes": [
+		{"pattern": "/users", "handler": "user"},
+		{"pattern": "/users", "handler": "user", "methods": ["GET"]}
+	]}`))
+	a.NotError(err)
+	errs, ok = conf.Register(m, registry).(Errors)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Line, 3)
+}
+
+func TestParseJSON(t *testing.T) {
+	a := assert.New(t)
+
+	conf, err := ParseJSON([]byte(`{
+  "routes": [
+    {"pattern": "/posts", "handler": "posts"},
+
+    {
+      "pattern": "/users",
+      "handler": "user"
+    }
+  ]
+}`))
+	a.NotError(err).Equal(len(conf.Routes), 2)
+	a.Equal(conf.Routes[0].Line(), 3).Equal(conf.Routes[0].Pattern, "/posts")
+	a.Equal(conf.Routes[1].Line(), 5).Equal(conf.Routes[1].Handler, "user")
+
+	test := func(data string, line int) {
+		conf, err := ParseJSON([]byte(data))
+		a.Error(err).Nil(conf)
+		errs, ok := err.(Errors)
+		a.True(ok).Equal(len(errs), 1)
+		a.Equal(errs[0].Line, line, "%s 的行号为 %d", data, errs[0].Line)
+	}
+
+	test("[]", 1)
+	test("{\n\"routes\": [\n{\"pattern\": 5}]}", 3)
+	test("{\n\"routes\": [\n{\"pattern\": \"/\",\n\"x\": 1}]}", 3)
+	test("{\n\"routes\": [],\n\"x\": 1}", 3)
+	test("{\n\"routes\": [\n{\"pattern\" \"/\"}]}", 3)
+	test("{\"routes\": []}\n{}", 2)
+	test("{\"routes\": [\n", 2)
+}
+
+func TestParseYAML(t *testing.T) {
+	a := assert.New(t)
+
+	conf, err := ParseYAML([]byte(`routes:
+- pattern: "/posts/{id:\\d+}" # 注释
+  name: 'it''s'
+  methods:
+  - GET
+  - "POST"
+- handler: "a#b"
+  methods: DELETE
+`))
+	a.NotError(err).Equal(len(conf.Routes), 2)
+	a.Equal(conf.Routes[0], &Route{
+		Pattern: "/posts/{id:\\d+}",
+		Name:    "it's",
+		Methods: []string{"GET", "POST"},
+		line:    2,
+	})
+	a.Equal(conf.Routes[1], &Route{
+		Handler: "a#b",
+		Methods: []string{"DELETE"},
+		line:    7,
+	})
+
+	conf, err = ParseYAML([]byte("# 空的配置\nroutes: []\n"))
+	a.NotError(err).Empty(conf.Routes)
+
+	test := func(data string, line int) {
+		conf, err := ParseYAML([]byte(data))
+		a.Error(err).Nil(conf)
+		errs, ok := err.(Errors)
+		a.True(ok).Equal(len(errs), 1)
+		a.Equal(errs[0].Line, line, "%s 的行号为 %d", data, errs[0].Line)
+	}
+
+	test("routes: abc", 1)
+	test("foo:\n", 1)
+	test("routes:\n  - pattern: /\n\thandler: a", 3)
+	test("routes:\n  - pattern: /\n   handler: a", 3)
+	test("routes:\n  - pattern: /\n    x: a", 3)
+	test("routes:\n  - pattern: /\n    handler: \"a", 3)
+	test("routes:\n  - pattern: /\n    methods: [GET", 3)
+	test("routes:\n  - pattern: /\n  - pattern: {a}", 3)
+	test("routes:\n  -\n", 2)
+	test("routes:\n  - pattern: /\nfoo: a", 3)
+}
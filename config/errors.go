@@ -0,0 +1,39 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error 表示配置文件中某一行的错误信息
+type Error struct {
+	File    string // 文件名，非从文件中加载时为空
+	Line    int    // 行号，从 1 开始
+	Message string
+}
+
+// Errors 表示多条错误信息
+type Errors []*Error
+
+func newError(line int, msg string) *Error {
+	return &Error{Line: line, Message: msg}
+}
+
+func (err *Error) Error() string {
+	if err.File == "" {
+		return fmt.Sprintf("第 %d 行：%s", err.Line, err.Message)
+	}
+	return fmt.Sprintf("%s 第 %d 行：%s", err.File, err.Line, err.Message)
+}
+
+func (errs Errors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ParseJSON 解析 JSON 格式的配置内容。
+//
+// 返回的错误为 Errors 类型，包含出错的行号。
+func ParseJSON(data []byte) (*Config, error) {
+	p := &jsonParser{
+		data: data,
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	p.dec.DisallowUnknownFields()
+
+	conf, err := p.parse()
+	if err != nil {
+		return nil, Errors{p.error(err)}
+	}
+	return conf, nil
+}
+
+type jsonParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// 带位置信息的错误
+type jsonError struct {
+	offset int
+	msg    string
+}
+
+func (err *jsonError) Error() string {
+	return err.msg
+}
+
+func (p *jsonParser) parse() (*Config, error) {
+	if err := p.delim('{'); err != nil {
+		return nil, err
+	}
+
+	conf := &Config{}
+	for p.dec.More() {
+		offset := p.next()
+		token, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if token != "routes" {
+			return nil, &jsonError{offset: offset, msg: fmt.Sprintf("未知的字段 %v", token)}
+		}
+
+		if conf.Routes, err = p.parseRoutes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.delim('}'); err != nil {
+		return nil, err
+	}
+
+	offset := p.next()
+	if _, err := p.dec.Token(); err != io.EOF {
+		return nil, &jsonError{offset: offset, msg: "多余的内容"}
+	}
+
+	return conf, nil
+}
+
+func (p *jsonParser) parseRoutes() ([]*Route, error) {
+	if err := p.delim('['); err != nil {
+		return nil, err
+	}
+
+	routes := make([]*Route, 0, 10)
+	for p.dec.More() {
+		offset := p.next()
+		route := &Route{line: p.line(offset)}
+		if err := p.dec.Decode(route); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				return nil, err
+			}
+			if e, ok := err.(*json.UnmarshalTypeError); ok { // Offset 是相对于当前值的
+				return nil, &jsonError{offset: offset + int(e.Offset), msg: e.Error()}
+			}
+			return nil, &jsonError{offset: offset, msg: err.Error()} // 未知字段等错误，以路由项的起始行为准
+		}
+		routes = append(routes, route)
+	}
+
+	if err := p.delim(']'); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// 读取一个分隔符，若不是 delim，则返回错误。
+func (p *jsonParser) delim(delim json.Delim) error {
+	offset := p.next()
+
+	token, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return &jsonError{offset: offset, msg: fmt.Sprintf("期望 %v，实际为 %v", delim, token)}
+	}
+	return nil
+}
+
+// 下一个值的起始位置，跳过空白字符和分隔值的逗号。
+func (p *jsonParser) next() int {
+	offset := int(p.dec.InputOffset())
+	for ; offset < len(p.data); offset++ {
+		switch p.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// 将 offset 转换成行号
+func (p *jsonParser) line(offset int) int {
+	if offset > len(p.data) {
+		offset = len(p.data)
+	}
+	return bytes.Count(p.data[:offset], []byte{'\n'}) + 1
+}
+
+// 将错误转换成包含行号的 *Error
+func (p *jsonParser) error(err error) *Error {
+	switch e := err.(type) {
+	case *jsonError:
+		return newError(p.line(e.offset), e.msg)
+	case *json.SyntaxError:
+		return newError(p.line(int(e.Offset)), e.Error())
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return newError(p.line(len(p.data)), "非预期的文件结尾")
+	}
+	return newError(p.line(p.next()), err.Error())
+}
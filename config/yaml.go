@@ -0,0 +1,240 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseYAML 解析 YAML 格式的配置内容。
+//
+// 仅支持配置文件所需要的 YAML 子集：顶层的 routes 字段，
+// 其值为由映射组成的块序列，映射的值可以是标量，methods 字段还可以是流序列
+// [GET, POST] 或是块序列。标量可以是纯文本，也可以是单引号或双引号包含的字符串。
+// 支持以 # 开头的注释，不支持 Tab 缩进。
+//
+// 返回的错误为 Errors 类型，包含出错的行号。
+func ParseYAML(data []byte) (*Config, error) {
+	p := &yamlParser{conf: &Config{}}
+
+	for i, line := range bytes.Split(data, []byte{'\n'}) {
+		if err := p.parseLine(i+1, string(line)); err != nil {
+			return nil, Errors{newError(i+1, err.Error())}
+		}
+	}
+
+	return p.conf, nil
+}
+
+type yamlParser struct {
+	conf   *Config
+	routes bool // 是否已经读取到 routes 字段
+
+	route     *Route // 当前正在解析的路由项
+	itemDepth int    // 当前路由项中 - 的缩进
+	keyDepth  int    // 当前路由项中键名的缩进
+
+	list bool // 是否正在读取 methods 的块序列
+}
+
+func (p *yamlParser) parseLine(lineno int, line string) error {
+	line, err := stripComment(strings.TrimRight(line, "\r"))
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimLeft(line, " ")
+	if content == "" {
+		return nil
+	}
+	if content[0] == '\t' {
+		return fmt.Errorf("不能使用 Tab 进行缩进")
+	}
+	depth := len(line) - len(content)
+
+	if !p.routes {
+		key, val := splitKeyValue(content)
+		if depth != 0 || key != "routes" {
+			return fmt.Errorf("未知的字段 %s", content)
+		}
+		if val != "" && val != "[]" {
+			return fmt.Errorf("routes 的值必须为序列")
+		}
+		p.routes = true
+		return nil
+	}
+
+	// methods 的块序列
+	if p.list && depth >= p.keyDepth && depth != p.itemDepth && isItem(content) {
+		val, err := parseScalar(strings.TrimSpace(content[1:]))
+		if err != nil {
+			return err
+		}
+		p.route.Methods = append(p.route.Methods, val)
+		return nil
+	}
+	p.list = false
+
+	switch {
+	case isItem(content): // 新的路由项
+		if p.route != nil && depth != p.itemDepth {
+			return fmt.Errorf("错误的缩进")
+		}
+
+		rest := strings.TrimLeft(content[1:], " ")
+		p.route = &Route{line: lineno}
+		p.conf.Routes = append(p.conf.Routes, p.route)
+		p.itemDepth = depth
+		p.keyDepth = depth + len(content) - len(rest)
+		if rest == "" {
+			return fmt.Errorf("路由项不能为空")
+		}
+		return p.parseField(rest)
+	case p.route != nil && depth == p.keyDepth:
+		return p.parseField(content)
+	default:
+		return fmt.Errorf("错误的缩进")
+	}
+}
+
+// 解析路由项中的单个字段
+func (p *yamlParser) parseField(content string) error {
+	key, val := splitKeyValue(content)
+	if key == "" {
+		return fmt.Errorf("无效的内容 %s", content)
+	}
+
+	if key == "methods" {
+		switch {
+		case val == "":
+			p.list = true
+			return nil
+		case val[0] == '[':
+			methods, err := parseFlowSequence(val)
+			if err != nil {
+				return err
+			}
+			p.route.Methods = methods
+			return nil
+		}
+	}
+
+	v, err := parseScalar(val)
+	if err != nil {
+		return err
+	}
+
+	switch key {
+	case "pattern":
+		p.route.Pattern = v
+	case "name":
+		p.route.Name = v
+	case "handler":
+		p.route.Handler = v
+	case "methods":
+		p.route.Methods = []string{v}
+	default:
+		return fmt.Errorf("未知的字段 %s", key)
+	}
+	return nil
+}
+
+// 是否为序列中的元素
+func isItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// 拆分 key: value 格式的内容，若不是该格式，则返回的 key 为空。
+func splitKeyValue(content string) (key, val string) {
+	if strings.HasSuffix(content, ":") {
+		return strings.TrimSpace(content[:len(content)-1]), ""
+	}
+
+	index := strings.Index(content, ": ")
+	if index <= 0 {
+		return "", ""
+	}
+	return strings.TrimSpace(content[:index]), strings.TrimSpace(content[index+2:])
+}
+
+// 解析 [a, b] 格式的流序列
+func parseFlowSequence(val string) ([]string, error) {
+	if val[len(val)-1] != ']' {
+		return nil, fmt.Errorf("无效的序列 %s", val)
+	}
+
+	val = strings.TrimSpace(val[1 : len(val)-1])
+	if val == "" {
+		return nil, nil
+	}
+
+	items := strings.Split(val, ",")
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		v, err := parseScalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// 解析标量，去掉引号。
+func parseScalar(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+
+	switch val[0] {
+	case '"':
+		v, err := strconv.Unquote(val)
+		if err != nil {
+			return "", fmt.Errorf("无效的字符串 %s", val)
+		}
+		return v, nil
+	case '\'':
+		if len(val) < 2 || val[len(val)-1] != '\'' {
+			return "", fmt.Errorf("无效的字符串 %s", val)
+		}
+		return strings.Replace(val[1:len(val)-1], "''", "'", -1), nil
+	case '[', '{', '&', '*', '!', '|', '>', '%', '@', '`':
+		return "", fmt.Errorf("不支持的值 %s", val)
+	}
+
+	return val, nil
+}
+
+// 去掉行中的注释，引号中的 # 不作为注释。
+func stripComment(line string) (string, error) {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '[' || line[i-1] == ',' {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || line[i-1] == ' ' {
+				return strings.TrimRight(line[:i], " "), nil
+			}
+		}
+	}
+
+	if quote != 0 {
+		return "", fmt.Errorf("字符串缺少结束的引号")
+	}
+	return strings.TrimRight(line, " "), nil
+}
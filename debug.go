@@ -0,0 +1,218 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/issue9/mux/internal/tree"
+)
+
+type (
+	// Node 表示路由树中的节点，可通过 Mux.Nodes 获取，一般用于调试。
+	Node = tree.Node
+
+	// Trace 表示某一请求在路由树中的匹配过程，可通过 Mux.Explain 获取，一般用于调试。
+	Trace = tree.Trace
+
+	// TraceStep 表示匹配过程中对单个节点的比较结果。
+	TraceStep = tree.TraceStep
+)
+
+// Nodes 获取路由树的所有节点
+func (mux *Mux) Nodes() []*Node {
+	return mux.tree.Nodes()
+}
+
+// Routes 获取所有的路由项，按匹配模式排序。
+func (mux *Mux) Routes() []*Route {
+	return mux.tree.Routes()
+}
+
+// Route 获取匹配模式为 pattern 的路由项，不存在时返回 nil。
+func (mux *Mux) Route(pattern string) *Route {
+	hs, err := mux.find(pattern)
+	if err != nil {
+		return nil
+	}
+	return hs.Route()
+}
+
+// Names 获取所有通过 Mux.Name 命名的路由项，键名为名称，键值为匹配模式。
+//
+// 返回的是一份副本，修改它不会影响 Mux 本身。
+func (mux *Mux) Names() map[string]string {
+	mux.namesMu.RLock()
+	defer mux.namesMu.RUnlock()
+
+	names := make(map[string]string, len(mux.names))
+	for name, pattern := range mux.names {
+		names[name] = pattern
+	}
+	return names
+}
+
+// Print 向 w 输出路由树的结构，包括各个路由项的完整匹配模式、请求方法和名称。
+func (mux *Mux) Print(w io.Writer) {
+	mux.tree.Print(w)
+}
+
+// Dot 以 Graphviz 的 DOT 格式向 w 输出路由树的结构。
+//
+// 可以通过 dot -Tsvg 等命令将其转换成图片。
+func (mux *Mux) Dot(w io.Writer) {
+	mux.tree.Dot(w)
+}
+
+// Explain 获取请求 method 和 path 在路由树中的匹配过程。
+//
+// 与 ServeHTTP 一样，会根据 New() 中的 skipCleanPath 参数决定是否处理 path。
+func (mux *Mux) Explain(method, path string) *Trace {
+	if !mux.skipCleanPath {
+		path = cleanPath(path)
+	}
+
+	return mux.tree.Explain(method, path)
+}
+
+// Trace 向 w 输出请求 method 和 path 在路由树中的匹配过程，
+// 包括与每个节点比较的结果以及原因。
+func (mux *Mux) Trace(w io.Writer, method, path string) {
+	mux.Explain(method, path).Print(w)
+}
+
+// 调试页面中的具名路由项
+type debugName struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// 调试页面的数据
+type debugData struct {
+	Tree   string       `json:"tree"`
+	Nodes  []*Node      `json:"nodes"`
+	Routes []*Route     `json:"routes"`
+	Names  []*debugName `json:"names"`
+	Trace  *Trace       `json:"trace,omitempty"`
+}
+
+// DebugHandler 返回一个用于查看 m 中路由信息的 http.Handler。
+//
+// 输出的内容包括路由树的结构、所有的路由项及其 Allow 报头、
+// 所有的具名路由项，并提供了一个表单用于查看某一请求的匹配过程。
+// 可以通过查询参数 method 和 path 指定需要查看匹配过程的请求。
+//
+// 默认输出 HTML 页面，当查询参数 format 为 json，
+// 或是 Accept 报头倾向于 application/json 时，输出 JSON 格式的内容；
+// format 为 dot 时，输出由 Mux.Dot 生成的内容。
+//
+// 输出的内容包含了所有的路由信息，不应该挂载在对外公开的地址上：
+//  http.ListenAndServe("127.0.0.1:8081", mux.DebugHandler(m))
+func DebugHandler(m *Mux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("format") == "dot" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			m.Dot(w)
+			return
+		}
+
+		data := m.debugData(r)
+		if query.Get("format") == "json" ||
+			(query.Get("format") == "" && acceptJSON(r.Header.Get("Accept"))) {
+			w.Header().Set("Content-Type", jsonContentType+"; charset=utf-8")
+			json.NewEncoder(w).Encode(data)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := debugTemplate.Execute(w, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func (mux *Mux) debugData(r *http.Request) *debugData {
+	buf := new(bytes.Buffer)
+	mux.Print(buf)
+
+	names := mux.Names()
+	data := &debugData{
+		Tree:   buf.String(),
+		Nodes:  mux.Nodes(),
+		Routes: mux.Routes(),
+		Names:  make([]*debugName, 0, len(names)),
+	}
+
+	for name, pattern := range names {
+		data.Names = append(data.Names, &debugName{Name: name, Pattern: pattern})
+	}
+	sort.Slice(data.Names, func(i, j int) bool {
+		return data.Names[i].Name < data.Names[j].Name
+	})
+
+	query := r.URL.Query()
+	if path := query.Get("path"); path != "" {
+		method := query.Get("method")
+		if method == "" {
+			method = http.MethodGet
+		}
+		data.Trace = mux.Explain(method, path)
+	}
+
+	return data
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<title>mux</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+.matched { color: green; }
+.mismatched { color: #999; }
+</style>
+</head>
+<body>
+<h2>Trace</h2>
+<form method="get">
+<input type="text" name="method" value="{{if .Trace}}{{.Trace.Method}}{{else}}GET{{end}}" size="8" />
+<input type="text" name="path" value="{{if .Trace}}{{.Trace.Path}}{{end}}" size="60" placeholder="/path" />
+<input type="submit" value="trace" />
+</form>
+{{with .Trace}}
+<p>{{.Method}} {{.Path}}: <strong>{{.Status}}</strong>{{with .Route}} &rArr; {{.Pattern}}{{end}}{{with .Params}} {{.}}{{end}}</p>
+<table>
+<tr><th>depth</th><th>pattern</th><th>type</th><th>path</th><th>reason</th></tr>
+{{range .Steps}}<tr class="{{if .Matched}}matched{{else}}mismatched{{end}}"><td>{{.Depth}}</td><td>{{.Pattern}}</td><td>{{.Type}}</td><td>{{.Path}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<h2>Routes</h2>
+<table>
+<tr><th>pattern</th><th>name</th><th>methods</th><th>allow</th></tr>
+{{range .Routes}}<tr><td>{{.Pattern}}</td><td>{{.Name}}</td><td>{{range $i, $m := .Methods}}{{if $i}}, {{end}}{{$m}}{{end}}</td><td>{{.Allow}}</td></tr>
+{{end}}</table>
+
+<h2>Names</h2>
+<table>
+<tr><th>name</th><th>pattern</th></tr>
+{{range .Names}}<tr><td>{{.Name}}</td><td>{{.Pattern}}</td></tr>
+{{end}}</table>
+
+<h2>Tree</h2>
+<pre>{{.Tree}}</pre>
+
+<p><a href="?format=json{{with .Trace}}&amp;method={{.Method}}&amp;path={{.Path}}{{end}}">JSON</a> <a href="?format=dot">DOT</a></p>
+</body>
+</html>
+`))
@@ -0,0 +1,168 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestMux_Nodes_Print(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1)).
+		Post("/posts/{id:\\d+}", buildHandler(1)).
+		Get("/posts", buildHandler(1))
+	a.NotError(srvmux.Name("post", "/posts/{id:\\d+}"))
+
+	nodes := srvmux.Nodes()
+	a.Equal(len(nodes), 1)
+	a.Equal(nodes[0].Pattern, "/posts").NotNil(nodes[0].Route)
+
+	data, err := json.Marshal(nodes)
+	a.NotError(err).NotEmpty(data)
+
+	w := new(bytes.Buffer)
+	srvmux.Print(w)
+	a.Equal(w.String(), `/posts    => /posts [GET, OPTIONS]
+    /
+        {id:\d+}    => /posts/{id:\d+} [GET, OPTIONS, POST] (post)
+`)
+}
+
+func TestMux_Explain_Trace(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1))
+
+	trace := srvmux.Explain(http.MethodGet, "//posts/1")
+	a.Equal(trace.Path, "/posts/1").
+		Equal(trace.Method, http.MethodGet).
+		Equal(trace.Status, http.StatusOK).
+		Equal(trace.Route.Pattern(), "/posts/{id:\\d+}").
+		Equal(trace.Params, map[string]string{"id": "1"})
+
+	a.Equal(srvmux.Explain(http.MethodGet, "/posts/abc").Status, http.StatusNotFound)
+	a.Equal(srvmux.Explain(http.MethodPost, "/posts/1").Status, http.StatusMethodNotAllowed)
+	a.Equal(srvmux.Explain("FOO", "/posts/1").Status, http.StatusNotImplemented)
+
+	data, err := json.Marshal(trace)
+	a.NotError(err).NotEmpty(data)
+
+	w := new(bytes.Buffer)
+	srvmux.Trace(w, http.MethodGet, "/posts/1")
+	a.True(strings.HasPrefix(w.String(), "GET /posts/1\n"))
+	a.True(strings.Contains(w.String(), "route: /posts/{id:\\d+}"))
+}
+
+func TestMux_Routes_Names(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1)).
+		Get("/posts", buildHandler(1))
+	a.NotError(srvmux.Name("post", "/posts/{id:\\d+}"))
+
+	routes := srvmux.Routes()
+	a.Equal(len(routes), 2)
+	a.Equal(routes[0].Pattern(), "/posts").
+		Equal(routes[1].Pattern(), "/posts/{id:\\d+}").
+		Equal(routes[1].Name(), "post")
+
+	a.Equal(srvmux.Route("/posts"), routes[0]).
+		Equal(srvmux.Prefix("/posts").Route("/{id:\\d+}"), routes[1]).
+		Nil(srvmux.Route("/posts/{id}")).
+		Nil(srvmux.Route("/posts/{id"))
+
+	names := srvmux.Names()
+	a.Equal(names, map[string]string{"post": "/posts/{id:\\d+}"})
+	names["post"] = "/"
+	a.Equal(srvmux.Names()["post"], "/posts/{id:\\d+}")
+}
+
+func TestDebugHandler(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1))
+	a.NotError(srvmux.Name("post", "/posts/{id:\\d+}"))
+	h := DebugHandler(srvmux)
+
+	// HTML
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/debug?method=POST&path=/posts/1", nil)
+	h.ServeHTTP(w, r)
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	body := w.Body.String()
+	a.True(strings.Contains(body, "/posts/{id:\\d&#43;}")) // + 会被转义
+	a.True(strings.Contains(body, "<strong>405</strong>"))
+
+	// JSON
+	test := func(r *http.Request) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		a.Equal(w.Code, http.StatusOK).
+			Equal(w.Header().Get("Content-Type"), "application/json; charset=utf-8")
+
+		data := &struct {
+			Tree   string
+			Routes []map[string]interface{}
+			Names  []map[string]string
+			Trace  *struct {
+				Method string
+				Path   string
+				Status int
+				Params map[string]string
+			}
+		}{}
+		a.NotError(json.Unmarshal(w.Body.Bytes(), data))
+		a.NotEmpty(data.Tree)
+		a.Equal(len(data.Routes), 1).
+			Equal(data.Routes[0]["pattern"], "/posts/{id:\\d+}").
+			Equal(data.Routes[0]["allow"], "GET, OPTIONS")
+		a.Equal(data.Names, []map[string]string{{"name": "post", "pattern": "/posts/{id:\\d+}"}})
+		a.NotNil(data.Trace).
+			Equal(data.Trace.Method, http.MethodGet).
+			Equal(data.Trace.Status, http.StatusOK).
+			Equal(data.Trace.Params, map[string]string{"id": "1"})
+	}
+
+	test(httptest.NewRequest(http.MethodGet, "/debug?format=json&path=/posts/1", nil))
+
+	r = httptest.NewRequest(http.MethodGet, "/debug?path=/posts/1", nil)
+	r.Header.Set("Accept", "application/json")
+	test(r)
+
+	// DOT
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/debug?format=dot", nil)
+	h.ServeHTTP(w, r)
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Type"), "text/vnd.graphviz; charset=utf-8")
+	a.True(strings.HasPrefix(w.Body.String(), "digraph mux {"))
+}
+
+func TestMux_Dot(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1))
+
+	w := new(bytes.Buffer)
+	srvmux.Dot(w)
+	a.True(strings.HasPrefix(w.String(), "digraph mux {"))
+	a.True(strings.Contains(w.String(), `label="{id:\\d+}\nregexp`))
+}
@@ -0,0 +1,21 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package dialect 将其它路由库的匹配模式转换成当前包的语法。
+//
+// 目前支持 http.ServeMux 的语法：
+//  methods, pattern, err := dialect.ServeMux("GET /items/{id}")
+//  // methods: [GET, HEAD]
+//  // pattern: /items/{id:[^/]+}
+//
+// 以及 httprouter、gorilla/mux 和 chi 的语法：
+//  pattern, err := dialect.HTTPRouter("/users/:id") // /users/{id:[^/]+}
+//  pattern, err := dialect.Gorilla("/users/{id:[0-9]+}") // /users/{id:[0-9]+}
+//
+// 迁移整个路由表：
+//  err := dialect.Migrate(m, dialect.HTTPRouter, []*dialect.Route{
+//      {Methods: []string{"GET"}, Pattern: "/users/:id", Handler: h},
+//      {Methods: []string{"GET"}, Pattern: "/src/*filepath", Handler: h},
+//  })
+package dialect
@@ -0,0 +1,105 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/issue9/mux/internal/tree"
+)
+
+// ChiWildcard chi 中以 * 结尾的匹配模式，剩余的路径保存在该名称的参数中，
+// 与 chi.URLParam(r, "*") 相同。
+const ChiWildcard = "*"
+
+// Gorilla 将 github.com/gorilla/mux 格式的匹配模式转换成当前包的语法。
+//
+// 转换规则如下：
+//  /users/{id}          => /users/{id:[^/]+}     // 默认匹配单个路径段
+//  /users/{id:[0-9]+}   => /users/{id:[0-9]+}
+//
+// 以下情况无法转换：
+//  - 正则表达式中包含 { 和 } 字符，比如 {id:[0-9]{3}}；
+//  - 两个参数相邻，比如 {a}{b}。
+func Gorilla(pattern string) (string, error) {
+	return convertBraces(pattern, false)
+}
+
+// Chi 将 github.com/go-chi/chi 格式的匹配模式转换成当前包的语法。
+//
+// 转换规则与 Gorilla 相同，另外还支持以 * 结尾的匹配模式：
+//  /files/*  => /files/{*}  // 匹配剩余的所有路径，可以为空
+//
+// 需要注意的是，chi 中的正则表达式仅与单个路径段进行匹配，
+// 而转换之后的正则表达式可能会跨越多个路径段，比如 {path:.+}。
+func Chi(pattern string) (string, error) {
+	return convertBraces(pattern, true)
+}
+
+// 转换 gorilla/mux 和 chi 中以 {} 表示参数的匹配模式
+func convertBraces(pattern string, wildcard bool) (string, error) {
+	if pattern == "" || pattern[0] != '/' {
+		return "", fmt.Errorf("%s 必须以 / 开头", pattern)
+	}
+
+	names := make(map[string]bool, 5)
+	buf := new(strings.Builder)
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '}':
+			return "", fmt.Errorf("%s 中的 } 没有对应的 {", pattern)
+		case c == ':':
+			return "", fmt.Errorf("%s 中包含了无法表示的字符 :", pattern)
+		case c == '*' && wildcard:
+			if i != len(pattern)-1 {
+				return "", fmt.Errorf("%s 中的 * 只能出现在路径的最后", pattern)
+			}
+			buf.WriteString("{" + ChiWildcard + "}")
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("%s 中的 { 没有对应的 }", pattern)
+			}
+			end += i
+
+			param := pattern[i+1 : end]
+			if strings.IndexByte(param, '{') >= 0 || (end+1 < len(pattern) && pattern[end+1] == '}') {
+				return "", fmt.Errorf("%s 中的正则表达式包含了 {}，无法转换", pattern)
+			}
+
+			name, expr := param, "[^/]+"
+			if index := strings.IndexByte(param, ':'); index >= 0 {
+				name, expr = param[:index], param[index+1:]
+			}
+			if !isIdentifier(name) {
+				return "", fmt.Errorf("%s 中包含无效的参数名称 %s", pattern, name)
+			}
+			if expr == "" {
+				return "", fmt.Errorf("%s 中参数 %s 的正则表达式为空", pattern, name)
+			}
+			if names[name] {
+				return "", fmt.Errorf("%s 中包含重复的参数名称 %s", pattern, name)
+			}
+			names[name] = true
+
+			buf.WriteString("{" + name + ":" + expr + "}")
+			i = end
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return validate(buf.String())
+}
+
+// 检测转换之后的匹配模式是否合法
+func validate(pattern string) (string, error) {
+	if _, err := tree.Parse(pattern); err != nil {
+		return "", err
+	}
+	return pattern, nil
+}
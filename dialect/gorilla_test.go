@@ -0,0 +1,50 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestGorilla_Chi(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(t Translator, pattern, p string) {
+		pp, err := t(pattern)
+		a.NotError(err, "%s 出错：%v", pattern, err).Equal(pp, p)
+	}
+
+	for _, t := range []Translator{Gorilla, Chi} {
+		test(t, "/", "/")
+		test(t, "/users/{id}", "/users/{id:[^/]+}")
+		test(t, "/users/{id:[0-9]+}", "/users/{id:[0-9]+}")
+		test(t, "/users/{id:[0-9]+}/posts/{slug}.html", "/users/{id:[0-9]+}/posts/{slug:[^/]+}.html")
+		test(t, "/users/u{id:[0-9]+}", "/users/u{id:[0-9]+}")
+	}
+	test(Chi, "/files/*", "/files/{*}")
+	test(Gorilla, "/files/*", "/files/*") // gorilla/mux 中 * 为普通字符
+
+	fail := func(t Translator, pattern string) {
+		p, err := t(pattern)
+		a.Error(err, "%s 未出错", pattern).Empty(p)
+	}
+
+	for _, t := range []Translator{Gorilla, Chi} {
+		fail(t, "")
+		fail(t, "users")
+		fail(t, "/users/{id")
+		fail(t, "/users/id}")
+		fail(t, "/users/{}")
+		fail(t, "/users/{id:}")
+		fail(t, "/users/{1d}")
+		fail(t, "/users/{id:[0-9]{3}}")
+		fail(t, "/users/{id}/{id}")
+		fail(t, "/users/{a}{b}")
+		fail(t, "/users:batch")
+	}
+	fail(Chi, "/files/*/x")
+}
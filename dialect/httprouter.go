@@ -0,0 +1,74 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HTTPRouter 将 github.com/julienschmidt/httprouter 格式的匹配模式转换成当前包的语法。
+//
+// 转换规则如下：
+//  /users/:id         => /users/{id:[^/]+}     // 匹配单个路径段
+//  /users/user_:id    => /users/user_{id:[^/]+}
+//  /src/*filepath     => /src{filepath:/.*}    // 参数值包含起始的 /，与 httprouter 相同
+//
+// 普通路径中不能包含 {、} 和 : 字符，*filepath 只能出现在路径的最后。
+func HTTPRouter(pattern string) (string, error) {
+	if pattern == "" || pattern[0] != '/' {
+		return "", fmt.Errorf("%s 必须以 / 开头", pattern)
+	}
+
+	names := make(map[string]bool, 5)
+	buf := new(strings.Builder)
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '{', '}':
+			return "", fmt.Errorf("%s 中包含了无法表示的字符 %s", pattern, string(c))
+		case ':', '*':
+			end := strings.IndexByte(pattern[i:], '/')
+			if end < 0 {
+				end = len(pattern)
+			} else {
+				end += i
+			}
+
+			name := pattern[i+1 : end]
+			if !isIdentifier(name) {
+				return "", fmt.Errorf("%s 中包含无效的参数名称 %s", pattern, name)
+			}
+			if names[name] {
+				return "", fmt.Errorf("%s 中包含重复的参数名称 %s", pattern, name)
+			}
+			names[name] = true
+
+			if c == ':' {
+				buf.WriteString("{" + name + ":[^/]+}")
+				i = end - 1
+				continue
+			}
+
+			// *name 必须在最后，且前面为 /，参数值包含该 /。
+			if end != len(pattern) {
+				return "", fmt.Errorf("%s 中 *%s 只能出现在路径的最后", pattern, name)
+			}
+			if pattern[i-1] != '/' {
+				return "", fmt.Errorf("%s 中 *%s 之前必须为 /", pattern, name)
+			}
+			str := buf.String()
+			buf.Reset()
+			buf.WriteString(str[:len(str)-1])
+			buf.WriteString("{" + name + ":/.*}")
+			i = end
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return validate(buf.String())
+}
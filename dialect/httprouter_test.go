@@ -0,0 +1,43 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestHTTPRouter(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(pattern, p string) {
+		pp, err := HTTPRouter(pattern)
+		a.NotError(err, "%s 出错：%v", pattern, err).Equal(pp, p)
+	}
+
+	test("/", "/")
+	test("/users", "/users")
+	test("/users/:id", "/users/{id:[^/]+}")
+	test("/users/:id/posts/:pid", "/users/{id:[^/]+}/posts/{pid:[^/]+}")
+	test("/users/user_:id", "/users/user_{id:[^/]+}")
+	test("/src/*filepath", "/src{filepath:/.*}")
+	test("/*path", "{path:/.*}")
+
+	fail := func(pattern string) {
+		p, err := HTTPRouter(pattern)
+		a.Error(err, "%s 未出错", pattern).Empty(p)
+	}
+
+	fail("")
+	fail("users")
+	fail("/users/{id}")
+	fail("/users/:")
+	fail("/users/:id/:id")
+	fail("/users/:1d")
+	fail("/src/*filepath/x")
+	fail("/src/x*filepath")
+	fail("/users/:id/*path") // 转换之后两个参数相邻
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+// Translator 将其它路由库的匹配模式转换成当前包的语法，
+// HTTPRouter、Gorilla 和 Chi 均为该类型。
+type Translator func(pattern string) (string, error)
+
+// Router 可以注册路由项的对象，*mux.Mux 和 *mux.Prefix 均实现了该接口。
+//
+// Route 用于在注册之前检测路由项是否已经存在，不存在时返回 nil。
+type Router interface {
+	Handle(pattern string, h http.Handler, methods ...string) error
+	Route(pattern string) *handlers.Route // 即 *mux.Route
+}
+
+// Route 表示需要迁移的路由项
+type Route struct {
+	Methods []string // 为空表示除 OPTIONS 之外的所有请求方法
+	Pattern string   // 原路由库的匹配模式
+	Handler http.Handler
+}
+
+// RouteError 表示迁移单条路由项时的错误
+type RouteError struct {
+	Index   int    // 在路由表中的索引
+	Pattern string // 原路由库的匹配模式
+	Err     error
+}
+
+// MigrateError 表示 Migrate 中所有路由项的错误
+type MigrateError []*RouteError
+
+func (err *RouteError) Error() string {
+	return fmt.Sprintf("第 %d 条路由项 %s 出错：%s", err.Index, err.Pattern, err.Err.Error())
+}
+
+func (err MigrateError) Error() string {
+	msgs := make([]string, 0, len(err))
+	for _, e := range err {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Migrate 将 routes 中的匹配模式通过 t 进行转换，之后注册到 r 中。
+//
+// 只要有一条路由项无法转换，或是与 r 中已有的路由项以及 routes 中的其它路由项
+// 存在相同的请求方法，或是包含不支持的请求方法，就不会注册任何路由项，
+// 返回的错误为 MigrateError 类型，包含了所有出错的路由项。
+//
+// OPTIONS 可能由 r 自动生成，无法事先判断是否冲突，所以不在检测范围之内，
+// 此类在注册时才发现的错误同样以 MigrateError 返回，但之前已经注册的路由项不会被撤销。
+func Migrate(r Router, t Translator, routes []*Route) error {
+	var errs MigrateError
+	patterns := make([]string, 0, len(routes))
+
+	for i, route := range routes {
+		p, err := t(route.Pattern)
+		if err != nil {
+			errs = append(errs, &RouteError{Index: i, Pattern: route.Pattern, Err: err})
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if errs = check(r, patterns, routes); len(errs) > 0 {
+		return errs
+	}
+
+	for i, route := range routes {
+		if err := r.Handle(patterns[i], route.Handler, route.Methods...); err != nil {
+			errs = append(errs, &RouteError{Index: i, Pattern: route.Pattern, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// 检测 routes 中的请求方法是否合法，以及是否与 r 中或是 routes 中的其它路由项冲突。
+//
+// patterns 为 routes 转换之后的匹配模式，与 routes 一一对应。
+func check(r Router, patterns []string, routes []*Route) MigrateError {
+	var errs MigrateError
+	exists := make(map[string][]string, len(routes)) // 各个匹配模式已经占用的请求方法
+
+	for i, route := range routes {
+		p := patterns[i]
+		used, found := exists[p]
+		if !found {
+			if rr := r.Route(p); rr != nil {
+				used = rr.Methods()
+			}
+		}
+
+		methods := route.Methods
+		if len(methods) == 0 {
+			methods = anyMethods()
+		}
+
+		for _, m := range methods {
+			var err error
+			switch {
+			case !handlers.IsSupported(m):
+				err = fmt.Errorf("不支持的请求方法 %s", m)
+			case m != http.MethodOptions && inStrings(used, m):
+				err = fmt.Errorf("该请求方法 %s 已经存在", m)
+			}
+
+			if err != nil {
+				errs = append(errs, &RouteError{Index: i, Pattern: route.Pattern, Err: err})
+				break
+			}
+		}
+
+		exists[p] = append(used, methods...)
+	}
+
+	return errs
+}
+
+// 未指定请求方法时所代表的请求方法，即除 OPTIONS 之外的所有请求方法。
+func anyMethods() []string {
+	methods := handlers.Methods()
+	ret := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if m != http.MethodOptions {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+func inStrings(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
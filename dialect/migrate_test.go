@@ -0,0 +1,81 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+// 记录注册内容的 Router 实现
+type router struct {
+	patterns []string
+}
+
+func (r *router) Handle(pattern string, h http.Handler, methods ...string) error {
+	for _, p := range r.patterns {
+		if p == pattern {
+			return errors.New("已经存在")
+		}
+	}
+
+	r.patterns = append(r.patterns, pattern)
+	return nil
+}
+
+func (r *router) Route(pattern string) *handlers.Route {
+	return nil
+}
+
+func TestMigrate(t *testing.T) {
+	a := assert.New(t)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	r := &router{}
+	a.NotError(Migrate(r, HTTPRouter, []*Route{
+		{Methods: []string{http.MethodGet}, Pattern: "/users/:id", Handler: h},
+		{Pattern: "/src/*filepath", Handler: h},
+	}))
+	a.Equal(r.patterns, []string{"/users/{id:[^/]+}", "/src{filepath:/.*}"})
+
+	// 转换出错，不会注册任何路由项
+	r = &router{}
+	err := Migrate(r, Gorilla, []*Route{
+		{Pattern: "/users/{id}", Handler: h},
+		{Pattern: "/users/{id:[0-9]{3}}", Handler: h},
+		{Pattern: "/users/{a}{b}", Handler: h},
+	})
+	errs, ok := err.(MigrateError)
+	a.True(ok).Equal(len(errs), 2)
+	a.Equal(errs[0].Index, 1).Equal(errs[0].Pattern, "/users/{id:[0-9]{3}}")
+	a.Equal(errs[1].Index, 2).Equal(errs[1].Pattern, "/users/{a}{b}")
+	a.NotEmpty(err.Error())
+	a.Empty(r.patterns)
+
+	// 注册出错
+	r = &router{}
+	err = Migrate(r, Chi, []*Route{
+		{Pattern: "/users/{id}", Handler: h},
+		{Pattern: "/users/{id}", Handler: h},
+	})
+	errs, ok = err.(MigrateError)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Index, 1)
+	a.Empty(r.patterns)
+
+	// 不支持的请求方法
+	r = &router{}
+	err = Migrate(r, Chi, []*Route{
+		{Pattern: "/posts", Handler: h},
+		{Methods: []string{"not-exists"}, Pattern: "/users/{id}", Handler: h},
+	})
+	errs, ok = err.(MigrateError)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Index, 1)
+	a.Empty(r.patterns)
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+	"github.com/issue9/mux/dialect"
+	"github.com/issue9/mux/params"
+)
+
+// 转换之后的匹配模式在 mux.Mux 中的匹配结果
+func TestMigrate_Mux(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	a.NotNil(m)
+
+	var ps params.Params
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ps = mux.Params(r)
+	})
+
+	a.NotError(dialect.Migrate(m, dialect.HTTPRouter, []*dialect.Route{
+		{Methods: []string{http.MethodGet}, Pattern: "/users/:id", Handler: h},
+		{Methods: []string{http.MethodGet}, Pattern: "/src/*filepath", Handler: h},
+	}))
+	a.NotError(dialect.Migrate(m.Prefix("/chi"), dialect.Chi, []*dialect.Route{
+		{Methods: []string{http.MethodGet}, Pattern: "/posts/{id:[0-9]+}", Handler: h},
+		{Methods: []string{http.MethodGet}, Pattern: "/files/*", Handler: h},
+	}))
+
+	test := func(path string, code int, p params.Params) {
+		ps = nil
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		a.Equal(w.Code, code, "%s 的状态码为 %d", path, w.Code).
+			Equal(ps, p)
+	}
+
+	test("/users/1", http.StatusOK, params.Params{"id": "1"})
+	test("/users/1/2", http.StatusNotFound, nil)
+	test("/src/", http.StatusOK, params.Params{"filepath": "/"})
+	test("/src/a/b.go", http.StatusOK, params.Params{"filepath": "/a/b.go"})
+	test("/chi/posts/5", http.StatusOK, params.Params{"id": "5"})
+	test("/chi/posts/x", http.StatusNotFound, nil)
+	test("/chi/files/a/b", http.StatusOK, params.Params{"*": "a/b"})
+}
+
+// 中间的路由项与已有的路由项冲突时，不会注册任何路由项
+func TestMigrate_Mux_conflict(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	m.Get("/users/{id:[^/]+}", h)
+
+	err := dialect.Migrate(m, dialect.HTTPRouter, []*dialect.Route{
+		{Methods: []string{http.MethodGet}, Pattern: "/posts/:id", Handler: h},
+		{Methods: []string{http.MethodPost, http.MethodGet}, Pattern: "/users/:id", Handler: h},
+		{Pattern: "/src/*filepath", Handler: h},
+	})
+	errs, ok := err.(dialect.MigrateError)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Index, 1)
+	a.Nil(m.Route("/posts/{id:[^/]+}")).
+		Nil(m.Route("/src{filepath:/.*}")).
+		Equal(m.Route("/users/{id:[^/]+}").Methods(), []string{http.MethodGet, http.MethodOptions})
+
+	// 同一匹配模式的不同请求方法可以正常注册
+	a.NotError(dialect.Migrate(m, dialect.HTTPRouter, []*dialect.Route{
+		{Methods: []string{http.MethodPost}, Pattern: "/users/:id", Handler: h},
+		{Methods: []string{http.MethodDelete}, Pattern: "/users/:id", Handler: h},
+	}))
+	a.Equal(m.Route("/users/{id:[^/]+}").Methods(), []string{http.MethodDelete, http.MethodGet, http.MethodOptions, http.MethodPost})
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+// SubtreeParam 以 / 结尾的 http.ServeMux 匹配模式会匹配所有以该路径开头的请求，
+// 转换之后，剩余部分的路径保存在该名称的路由参数中。
+const SubtreeParam = "_"
+
+// ServeMux 将 http.ServeMux 格式的匹配模式转换成当前包的语法。
+//
+// pattern 的格式为 [METHOD ][HOST]/[PATH]，返回的 methods 为模式中指定的请求方法，
+// 未指定时为空，表示匹配所有的请求方法；其中 GET 会同时包含 HEAD，
+// 与 http.ServeMux 的行为保持一致。
+//
+// 各类语法的转换规则如下：
+//  /items/{id}        => /items/{id:[^/]+}    // 仅匹配单个路径段
+//  /files/{path...}   => /files/{path}        // 匹配剩余的所有路径，可以为空
+//  /items/{$}         => /items/              // 仅匹配 /items/
+//  /items/            => /items/{_}           // 匹配以 /items/ 开头的所有路径
+//
+// 以下情况与 http.ServeMux 不同：
+//  - 不支持 HOST 部分，会返回错误；
+//  - 匹配的优先级遵循当前包的规则，即普通路由优先于正则路由，正则路由优先于命名路由，
+//    在 http.ServeMux 中会造成冲突的模式，在这里不会报错；
+//  - 不会将 /items 重定向到 /items/；
+//  - 不会对模式中的 %XX 进行转义，且普通路径段中不能包含 : 字符。
+func ServeMux(pattern string) (methods []string, p string, err error) {
+	path := strings.TrimSpace(pattern)
+
+	if index := strings.IndexAny(path, " \t"); index >= 0 {
+		method := path[:index]
+		if !handlers.IsSupported(method) {
+			return nil, "", fmt.Errorf("不支持的请求方法 %s", method)
+		}
+
+		methods = []string{method}
+		if method == http.MethodGet {
+			methods = append(methods, http.MethodHead)
+		}
+		path = strings.TrimLeft(path[index:], " \t")
+	}
+
+	index := strings.IndexByte(path, '/')
+	switch {
+	case index < 0:
+		return nil, "", fmt.Errorf("%s 缺少路径部分", pattern)
+	case index > 0:
+		return nil, "", fmt.Errorf("%s 中包含了域名，无法转换", pattern)
+	}
+
+	if p, err = convertServeMux(path); err != nil {
+		return nil, "", err
+	}
+
+	if p, err = validate(p); err != nil {
+		return nil, "", err
+	}
+	return methods, p, nil
+}
+
+// 转换 http.ServeMux 中的路径部分
+func convertServeMux(path string) (string, error) {
+	segments := strings.Split(path[1:], "/")
+	names := make(map[string]bool, len(segments))
+	last := len(segments) - 1
+
+	buf := new(strings.Builder)
+	for i, seg := range segments {
+		buf.WriteByte('/')
+
+		if !strings.ContainsAny(seg, "{}") {
+			if strings.IndexByte(seg, ':') >= 0 {
+				return "", fmt.Errorf("路径段 %s 中包含了无法表示的字符 :", seg)
+			}
+
+			if i == last && seg == "" { // 以 / 结尾
+				buf.WriteString("{" + SubtreeParam + "}")
+			} else {
+				buf.WriteString(seg)
+			}
+			continue
+		}
+
+		if seg[0] != '{' || seg[len(seg)-1] != '}' || strings.Count(seg, "{") > 1 {
+			return "", fmt.Errorf("通配符 %s 必须占用整个路径段", seg)
+		}
+		name := seg[1 : len(seg)-1]
+
+		if name == "$" {
+			if i != last {
+				return "", errors.New("{$} 只能出现在路径的最后")
+			}
+			continue
+		}
+
+		multi := strings.HasSuffix(name, "...")
+		if multi {
+			if i != last {
+				return "", fmt.Errorf("%s 只能出现在路径的最后", seg)
+			}
+			name = name[:len(name)-3]
+		}
+
+		if !isIdentifier(name) {
+			return "", fmt.Errorf("无效的通配符名称 %s", name)
+		}
+		if names[name] {
+			return "", fmt.Errorf("重复的通配符名称 %s", name)
+		}
+		names[name] = true
+
+		if multi {
+			buf.WriteString("{" + name + "}")
+		} else {
+			buf.WriteString("{" + name + ":[^/]+}")
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// 是否为合法的参数名称，同时需要满足 Go 标识符和正则表达式分组名称的要求。
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
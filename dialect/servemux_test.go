@@ -0,0 +1,75 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestServeMux(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(pattern string, methods []string, p string) {
+		ms, pp, err := ServeMux(pattern)
+		a.NotError(err, "%s 出错：%v", pattern, err).
+			Equal(ms, methods).
+			Equal(pp, p)
+	}
+
+	test("/", nil, "/{_}")
+	test("/{$}", nil, "/")
+	test("/items", nil, "/items")
+	test("/items/", nil, "/items/{_}")
+	test("/items/{$}", nil, "/items/")
+	test("/items/{id}", nil, "/items/{id:[^/]+}")
+	test("/items/{id}/", nil, "/items/{id:[^/]+}/{_}")
+	test("/items/{id}/edit", nil, "/items/{id:[^/]+}/edit")
+	test("/files/{path...}", nil, "/files/{path}")
+	test("/{a}/{b_2}", nil, "/{a:[^/]+}/{b_2:[^/]+}")
+	test("GET /items", []string{http.MethodGet, http.MethodHead}, "/items")
+	test("POST \t /items", []string{http.MethodPost}, "/items")
+	test(" DELETE /items/{id} ", []string{http.MethodDelete}, "/items/{id:[^/]+}")
+
+	fail := func(pattern string) {
+		ms, p, err := ServeMux(pattern)
+		a.Error(err, "%s 未出错", pattern).Nil(ms).Empty(p)
+	}
+
+	fail("")
+	fail("items")
+	fail("GET items")
+	fail("FOO /items")
+	fail("example.com/items")
+	fail("GET example.com/")
+	fail("/items:batch")
+	fail("/items/{id}.html")
+	fail("/items/x{id}")
+	fail("/items/{id")
+	fail("/items/{{id}}")
+	fail("/items/{}")
+	fail("/items/{1d}")
+	fail("/items/{id-x}")
+	fail("/items/{id}/{id}")
+	fail("/items/{$}/x")
+	fail("/files/{path...}/x")
+	fail("/files/{...}")
+}
+
+func TestIsIdentifier(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isIdentifier("id"))
+	a.True(isIdentifier("_"))
+	a.True(isIdentifier("id_2"))
+	a.True(isIdentifier("ID"))
+
+	a.False(isIdentifier(""))
+	a.False(isIdentifier("2id"))
+	a.False(isIdentifier("id-2"))
+	a.False(isIdentifier("编号"))
+}
@@ -80,6 +80,64 @@
 //  // 或是
 //  id := params.MustInt("id", 0) // 0 表示在无法获取 id 参数的默认值
 //
+// 同时，路由参数也会写入到 http.Request 的 PathValue 中：
+//  id := r.PathValue("id")
+//
+// 通过 HandleE、GetE 等方法注册的处理函数可以直接返回错误，
+// 由 SetErrorHandler() 指定的函数统一处理，默认情况下参数错误会输出 400：
+//  m.GetE("/posts/{id:\\d+}", func(w http.ResponseWriter, r *http.Request) error {
+//      id, err := mux.Params(r).Int("id")
+//      if err != nil {
+//          return err
+//      }
+//      ...
+//  })
+//
+// 对于 JSON 接口，可以通过 JSON() 将普通的函数转换成 HandlerFuncE，
+// 自动完成报文的解码、路由参数和查询参数的绑定以及返回值的编码：
+//  m.GetE("/posts/{id:\\d+}", mux.JSON(func(ctx context.Context, req getPost) (*Post, error) {
+//      ...
+//  }))
+// 成功时输出 200，需要其它状态码的，可以使用 JSONStatus()：
+//  m.PostE("/posts", mux.JSONStatus(http.StatusCreated, func(ctx context.Context, req createPost) (*Post, error) {
+//      ...
+//  }))
+//
+//
+//
+// 路由项信息
+//
+// 通过 CurrentRoute() 可以获取与当前请求匹配的路由项信息，
+// 包括完整的匹配模式、名称以及支持的请求方法，适合用作统计或是日志的标识：
+//  route := mux.CurrentRoute(r)
+//  route.Pattern() // /posts/{id:\\d+}
+//  route.Name()    // 通过 Mux.Name() 指定的名称
+//
+// 还可以通过 SetMeta() 为路由项附加元数据，比如所属的团队、权限范围等，
+// 在中间件或是处理函数中通过 Meta() 获取：
+//  m.SetMeta("/posts/{id:\\d+}", "scope", "admin", http.MethodDelete)
+//  scope, found := mux.Meta(r, "scope")
+//
+// 路由项的变化可以通过 OnRouteAdded() 和 OnRouteRemoved() 进行跟踪，
+// 比如同步到服务注册中心或是刷新文档缓存：
+//  m.OnRouteAdded(func(pattern string, methods []string) {
+//      registry.Add(pattern, methods)
+//  })
+//
+// 可以为路由项或是 Prefix 指定处理时限以及请求报文的最大值，
+// 超时通过 http.Request.Context() 通知处理函数，HandlerFuncE 返回超时错误时输出 503，
+// 报文过大输出 413，路由项的设置优先于 Prefix：
+//  api := m.Prefix("/api").SetTimeout(5 * time.Second).SetMaxBodySize(1 << 20)
+//  api.Resource("/upload").PostFunc(h).SetTimeout(10 * time.Minute).SetMaxBodySize(1 << 30)
+//
+// 通过 SetMetrics() 可以按路由项和请求方法统计请求数量、状态码和耗时，
+// 并以 Prometheus 的文本格式输出：
+//  metrics := mux.NewMetrics()
+//  m.SetMetrics(metrics).Get("/metrics", metrics)
+//
+// 处理函数中的 panic 默认不会被捕获，可以通过 SetRecovery() 开启：
+//  m.SetRecovery(mux.DefaultRecoveryHandler) // 记录日志并输出 500
+//
 //
 //
 // OPTIONS
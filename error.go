@@ -0,0 +1,234 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/issue9/mux/params"
+)
+
+// HandlerFuncE 可以返回错误信息的处理函数。
+//
+// 返回的错误会交由 Mux.SetErrorHandler 指定的函数处理，
+// 在返回错误之前，不应该向 w 输出任何内容。
+type HandlerFuncE func(w http.ResponseWriter, r *http.Request) error
+
+// ErrorHandler 处理 HandlerFuncE 返回的错误
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// StatusError 表示带 HTTP 状态码的错误信息
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+// NewStatusError 声明一个带状态码的错误信息，err 可以为空。
+func NewStatusError(status int, err error) error {
+	return &StatusError{Status: status, Err: err}
+}
+
+func (err *StatusError) Error() string {
+	if err.Err == nil {
+		return http.StatusText(err.Status)
+	}
+	return err.Err.Error()
+}
+
+// Unwrap 返回具体的错误信息，方便 errors.Is 等函数的判断。
+func (err *StatusError) Unwrap() error {
+	return err.Err
+}
+
+// DefaultErrorHandler 默认的错误处理函数。
+//
+// 根据 err 的类型决定输出内容：
+//  - 路由参数的错误，即 *params.ParamError、params.BindError 和 params.ErrParamNotExists，
+//    由 WriteParamError 输出 400；
+//  - *StatusError 以其状态码输出，仅 4XX 的状态码会将错误信息作为 Problem.Detail 输出；
+//  - 其它错误均输出 500，不包含具体的错误信息。
+// 以上判断均会通过 errors.As 展开被包装的错误，输出格式可参考 WriteProblem。
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var pe *params.ParamError
+	var be params.BindError
+	var se *StatusError
+
+	switch {
+	case errors.As(err, &be):
+		WriteParamError(w, r, be)
+	case errors.As(err, &pe):
+		WriteParamError(w, r, pe)
+	case errors.Is(err, params.ErrParamNotExists):
+		WriteParamError(w, r, err)
+	case errors.As(err, &se):
+		p := NewProblem(w, r, se.Status)
+		if se.Status < http.StatusInternalServerError && se.Err != nil {
+			p.Detail = se.Err.Error()
+		}
+		WriteProblem(w, r, p)
+	default:
+		WriteProblem(w, r, NewProblem(w, r, http.StatusInternalServerError))
+	}
+}
+
+// SetErrorHandler 指定 HandlerFuncE 返回错误时的处理函数，为 nil 时恢复成 DefaultErrorHandler。
+//
+// 对所有通过 HandleE 等方法添加的路由项均有效，包括调用此方法之前添加的。
+func (mux *Mux) SetErrorHandler(h ErrorHandler) *Mux {
+	if h == nil {
+		h = DefaultErrorHandler
+	}
+	mux.errorHandler = h
+
+	return mux
+}
+
+// 将 f 转换成 http.Handler，返回的错误交由 Mux.errorHandler 处理。
+//
+// 报文超出大小限制以及处理超时的错误，分别交由 413 和 503 的处理函数处理，
+// 具体可参考 Mux.SetMaxBodySize 和 Mux.SetTimeout。
+func (mux *Mux) handlerE(f HandlerFuncE) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := f(w, r)
+		if err == nil {
+			return
+		}
+
+		var mbe *http.MaxBytesError
+		switch {
+		case errors.As(err, &mbe):
+			mux.requestEntityTooLarge(w, r)
+		case errors.Is(err, context.DeadlineExceeded) && r.Context().Err() != nil:
+			mux.serviceUnavailable(w, r)
+		default:
+			mux.errorHandler(w, r, err)
+		}
+	})
+}
+
+// HandleE 功能同 Mux.Handle()，但是将第二个参数换成了可以返回错误的 HandlerFuncE
+func (mux *Mux) HandleE(pattern string, f HandlerFuncE, methods ...string) error {
+	return mux.Handle(pattern, mux.handlerE(f), methods...)
+}
+
+func (mux *Mux) handleE(pattern string, f HandlerFuncE, methods ...string) *Mux {
+	return mux.handle(pattern, mux.handlerE(f), methods...)
+}
+
+// GetE 相当于 Mux.HandleE(pattern, f, http.MethodGet) 的简易写法
+func (mux *Mux) GetE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f, http.MethodGet)
+}
+
+// PostE 相当于 Mux.HandleE(pattern, f, http.MethodPost) 的简易写法
+func (mux *Mux) PostE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f, http.MethodPost)
+}
+
+// DeleteE 相当于 Mux.HandleE(pattern, f, http.MethodDelete) 的简易写法
+func (mux *Mux) DeleteE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f, http.MethodDelete)
+}
+
+// PutE 相当于 Mux.HandleE(pattern, f, http.MethodPut) 的简易写法
+func (mux *Mux) PutE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f, http.MethodPut)
+}
+
+// PatchE 相当于 Mux.HandleE(pattern, f, http.MethodPatch) 的简易写法
+func (mux *Mux) PatchE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f, http.MethodPatch)
+}
+
+// AnyE 相当于 Mux.HandleE(pattern, f) 的简易写法
+func (mux *Mux) AnyE(pattern string, f HandlerFuncE) *Mux {
+	return mux.handleE(pattern, f)
+}
+
+// HandleE 相当于 Mux.HandleE(prefix+pattern, f, methods...) 的简易写法
+func (p *Prefix) HandleE(pattern string, f HandlerFuncE, methods ...string) error {
+	return p.mux.HandleE(p.prefix+pattern, f, methods...)
+}
+
+func (p *Prefix) handleE(pattern string, f HandlerFuncE, methods ...string) *Prefix {
+	if err := p.HandleE(pattern, f, methods...); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// GetE 相当于 Mux.GetE(prefix+pattern, f) 的简易写法
+func (p *Prefix) GetE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f, http.MethodGet)
+}
+
+// PostE 相当于 Mux.PostE(prefix+pattern, f) 的简易写法
+func (p *Prefix) PostE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f, http.MethodPost)
+}
+
+// DeleteE 相当于 Mux.DeleteE(prefix+pattern, f) 的简易写法
+func (p *Prefix) DeleteE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f, http.MethodDelete)
+}
+
+// PutE 相当于 Mux.PutE(prefix+pattern, f) 的简易写法
+func (p *Prefix) PutE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f, http.MethodPut)
+}
+
+// PatchE 相当于 Mux.PatchE(prefix+pattern, f) 的简易写法
+func (p *Prefix) PatchE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f, http.MethodPatch)
+}
+
+// AnyE 相当于 Mux.AnyE(prefix+pattern, f) 的简易写法
+func (p *Prefix) AnyE(pattern string, f HandlerFuncE) *Prefix {
+	return p.handleE(pattern, f)
+}
+
+// HandleE 相当于 Mux.HandleE(pattern, f, methods...) 的简易写法
+func (r *Resource) HandleE(f HandlerFuncE, methods ...string) error {
+	return r.mux.HandleE(r.pattern, f, methods...)
+}
+
+func (r *Resource) handleE(f HandlerFuncE, methods ...string) *Resource {
+	if err := r.HandleE(f, methods...); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// GetE 相当于 Mux.GetE(pattern, f) 的简易写法
+func (r *Resource) GetE(f HandlerFuncE) *Resource {
+	return r.handleE(f, http.MethodGet)
+}
+
+// PostE 相当于 Mux.PostE(pattern, f) 的简易写法
+func (r *Resource) PostE(f HandlerFuncE) *Resource {
+	return r.handleE(f, http.MethodPost)
+}
+
+// DeleteE 相当于 Mux.DeleteE(pattern, f) 的简易写法
+func (r *Resource) DeleteE(f HandlerFuncE) *Resource {
+	return r.handleE(f, http.MethodDelete)
+}
+
+// PutE 相当于 Mux.PutE(pattern, f) 的简易写法
+func (r *Resource) PutE(f HandlerFuncE) *Resource {
+	return r.handleE(f, http.MethodPut)
+}
+
+// PatchE 相当于 Mux.PatchE(pattern, f) 的简易写法
+func (r *Resource) PatchE(f HandlerFuncE) *Resource {
+	return r.handleE(f, http.MethodPatch)
+}
+
+// AnyE 相当于 Mux.AnyE(pattern, f) 的简易写法
+func (r *Resource) AnyE(f HandlerFuncE) *Resource {
+	return r.handleE(f)
+}
@@ -0,0 +1,112 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux/params"
+)
+
+func TestStatusError(t *testing.T) {
+	a := assert.New(t)
+
+	err := NewStatusError(http.StatusNotFound, nil)
+	a.Equal(err.Error(), http.StatusText(http.StatusNotFound))
+
+	inner := errors.New("文章不存在")
+	err = NewStatusError(http.StatusNotFound, inner)
+	a.Equal(err.Error(), "文章不存在").True(errors.Is(err, inner))
+}
+
+func TestDefaultErrorHandler(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(err error, status int) *Problem {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/abc", nil)
+		r.Header.Set("Accept", "application/json")
+		DefaultErrorHandler(w, r, err)
+		a.Equal(w.Code, status)
+
+		p := &Problem{}
+		a.NotError(json.Unmarshal(w.Body.Bytes(), p))
+		a.Equal(p.Status, status)
+		return p
+	}
+
+	// 被包装的参数错误
+	_, err := Params(httptest.NewRequest(http.MethodGet, "/", nil)).Int("id")
+	err = fmt.Errorf("获取参数：%w", err)
+	p := test(err, http.StatusBadRequest)
+	a.Empty(p.Detail).Equal(len(p.InvalidParams), 1).Equal(p.InvalidParams[0].Name, "id")
+
+	// 不含参数名称的参数错误
+	err = fmt.Errorf("获取参数：%w", params.ErrParamNotExists)
+	p = test(err, http.StatusBadRequest)
+	a.Equal(p.Detail, err.Error()).Empty(p.InvalidParams)
+
+	_, err = params.Params{"id": "abc"}.Int("id")
+	p = test(fmt.Errorf("获取参数：%w", err), http.StatusBadRequest)
+	a.Equal(len(p.InvalidParams), 1).Equal(p.InvalidParams[0].Name, "id")
+
+	p = test(NewStatusError(http.StatusConflict, errors.New("已经存在")), http.StatusConflict)
+	a.Equal(p.Detail, "已经存在")
+
+	// 5XX 不输出具体的错误信息
+	p = test(NewStatusError(http.StatusServiceUnavailable, errors.New("数据库连接失败")), http.StatusServiceUnavailable)
+	a.Empty(p.Detail)
+
+	p = test(errors.New("数据库连接失败"), http.StatusInternalServerError)
+	a.Empty(p.Detail)
+}
+
+func TestMux_HandleE(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	ok := func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return nil
+	}
+	fail := func(w http.ResponseWriter, r *http.Request) error {
+		_, err := Params(r).Int("id")
+		return err
+	}
+
+	srvmux.GetE("/posts/{id}", fail).
+		PostE("/posts/{id}", ok)
+	srvmux.Prefix("/api").PutE("/posts/{id}", fail)
+	srvmux.Resource("/api/posts/{id}").DeleteE(ok)
+	a.NotError(srvmux.HandleE("/users", ok, http.MethodPatch))
+
+	test := func(method, path string, status int) {
+		w := httptest.NewRecorder()
+		srvmux.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		a.Equal(w.Code, status, "%s %s 的状态码为 %d", method, path, w.Code)
+	}
+
+	test(http.MethodGet, "/posts/abc", http.StatusBadRequest)
+	test(http.MethodPost, "/posts/abc", http.StatusAccepted)
+	test(http.MethodPut, "/api/posts/abc", http.StatusBadRequest)
+	test(http.MethodDelete, "/api/posts/abc", http.StatusAccepted)
+	test(http.MethodPatch, "/users", http.StatusAccepted)
+
+	// 修改错误处理函数，对之前添加的路由项同样有效。
+	srvmux.SetErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	test(http.MethodGet, "/posts/abc", http.StatusTeapot)
+
+	srvmux.SetErrorHandler(nil)
+	test(http.MethodGet, "/posts/abc", http.StatusBadRequest)
+}
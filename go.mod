@@ -1,5 +1,7 @@
 module github.com/issue9/mux
 
+go 1.22
+
 require (
 	github.com/dimfeld/httptreemux v5.0.1+incompatible
 	github.com/issue9/assert v1.0.0
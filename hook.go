@@ -0,0 +1,102 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import "sort"
+
+// RouteHook 路由项发生变化时的回调函数。
+//
+// pattern 为路由项的完整匹配模式，methods 为本次添加或删除的请求方法，
+// 按字母顺序排列，包含自动生成或删除的 OPTIONS。
+type RouteHook func(pattern string, methods []string)
+
+// OnRouteAdded 指定添加路由项时的回调函数，可以多次调用以指定多个回调函数。
+//
+// 所有通过 Mux、Prefix 和 Resource 添加的路由项，包括通过 Options 生成的路由项，
+// 都会触发此函数，回调函数在路由项添加成功之后同步执行，添加失败时不会触发。
+func (mux *Mux) OnRouteAdded(f RouteHook) *Mux {
+	mux.hooksMu.Lock()
+	mux.addedHooks = append(mux.addedHooks, f)
+	mux.hooksMu.Unlock()
+	return mux
+}
+
+// OnRouteRemoved 指定删除路由项时的回调函数，可以多次调用以指定多个回调函数。
+//
+// Mux.Remove、Mux.Clean、Prefix.Clean 以及 Resource 中的相应方法都会触发此函数，
+// 回调函数在路由项删除之后同步执行，只删除部分请求方法时，methods 仅包含被删除的部分。
+func (mux *Mux) OnRouteRemoved(f RouteHook) *Mux {
+	mux.hooksMu.Lock()
+	mux.removedHooks = append(mux.removedHooks, f)
+	mux.hooksMu.Unlock()
+	return mux
+}
+
+// 获取所有路由项及其请求方法，未指定 OnRouteRemoved 时返回 nil。
+func (mux *Mux) routeMethods() map[string][]string {
+	mux.hooksMu.RLock()
+	size := len(mux.removedHooks)
+	mux.hooksMu.RUnlock()
+	if size == 0 {
+		return nil
+	}
+
+	routes := mux.tree.Routes()
+	ret := make(map[string][]string, len(routes))
+	for _, r := range routes {
+		ret[r.Pattern()] = r.Methods()
+	}
+	return ret
+}
+
+// 比较 Mux.routeMethods 在操作前后的返回值，并对被删除的部分触发 OnRouteRemoved。
+func (mux *Mux) fireRemovedDiff(before, after map[string][]string) {
+	patterns := make([]string, 0, len(before))
+	for pattern := range before {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		mux.fire(&mux.removedHooks, pattern, diffMethods(before[pattern], after[pattern]))
+	}
+}
+
+// 获取 pattern 对应路由项的请求方法，路由项不存在时返回 nil。
+func (mux *Mux) methods(pattern string) []string {
+	if hs := mux.tree.Find(pattern); hs != nil {
+		return hs.Route().Methods()
+	}
+	return nil
+}
+
+// 触发 hooks 中的回调函数，hooks 为 Mux.addedHooks 或是 Mux.removedHooks。
+//
+// 回调函数执行时不持有 Mux.hooksMu，所以在回调函数中也可以继续添加回调函数。
+func (mux *Mux) fire(hooks *[]RouteHook, pattern string, methods []string) {
+	if len(methods) == 0 {
+		return
+	}
+
+	mux.hooksMu.RLock()
+	fs := *hooks
+	mux.hooksMu.RUnlock()
+
+	for _, f := range fs {
+		f(pattern, methods)
+	}
+}
+
+// 返回在 m1 中但不在 m2 中的请求方法，两者都为已排序的列表。
+func diffMethods(m1, m2 []string) []string {
+	var ret []string
+	for _, m := range m1 {
+		index := sort.SearchStrings(m2, m)
+		if index >= len(m2) || m2[index] != m {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestMux_OnRouteAdded_OnRouteRemoved(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+
+	var added, removed []string
+	srvmux.OnRouteAdded(func(pattern string, methods []string) {
+		added = append(added, pattern+" "+strings.Join(methods, ","))
+	}).OnRouteRemoved(func(pattern string, methods []string) {
+		removed = append(removed, pattern+" "+strings.Join(methods, ","))
+	})
+
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1)).
+		Post("/posts/{id:\\d+}", buildHandler(1))
+	a.Equal(added, []string{"/posts/{id:\\d+} GET,OPTIONS", "/posts/{id:\\d+} POST"})
+
+	// 通过 Prefix 和 Resource 添加
+	added = added[:0]
+	srvmux.Prefix("/api").Get("/users", buildHandler(1))
+	srvmux.Resource("/api/users").Delete(buildHandler(1))
+	a.Equal(added, []string{"/api/users GET,OPTIONS", "/api/users DELETE"})
+
+	// 添加失败，不触发
+	added = added[:0]
+	a.Error(srvmux.Handle("/api/users", buildHandler(1), http.MethodGet))
+	a.Empty(added)
+
+	// 删除部分请求方法
+	srvmux.Remove("/posts/{id:\\d+}", http.MethodPost)
+	a.Equal(removed, []string{"/posts/{id:\\d+} POST"})
+
+	// 删除最后一个请求方法，OPTIONS 也一并删除
+	removed = removed[:0]
+	srvmux.Remove("/posts/{id:\\d+}", http.MethodGet)
+	a.Equal(removed, []string{"/posts/{id:\\d+} GET,OPTIONS"})
+
+	// 删除不存在的路由项，不触发
+	removed = removed[:0]
+	srvmux.Remove("/not-exists")
+	a.Empty(removed)
+
+	// Prefix.Clean
+	srvmux.Get("/posts", buildHandler(1))
+	srvmux.Prefix("/api").Clean()
+	a.Equal(removed, []string{"/api/users DELETE,GET,OPTIONS"})
+
+	// Mux.Clean
+	removed = removed[:0]
+	srvmux.Clean()
+	a.Equal(removed, []string{"/posts GET,OPTIONS"})
+
+	// 通过 Options 生成路由项
+	added = added[:0]
+	srvmux.Options("/options", "GET")
+	a.Equal(added, []string{"/options OPTIONS"})
+	added = added[:0]
+	srvmux.Get("/options", buildHandler(1))
+	a.Equal(added, []string{"/options GET"})
+
+	// 禁用 OPTIONS 的 Mux 中，新的路由项仅包含指定的请求方法
+	srvmux = New(true, false, nil, nil).OnRouteAdded(func(pattern string, methods []string) {
+		added = append(added, pattern+" "+strings.Join(methods, ","))
+	}).OnRouteRemoved(func(pattern string, methods []string) {
+		removed = append(removed, pattern+" "+strings.Join(methods, ","))
+	})
+	added = added[:0]
+	srvmux.Options("/options", "GET")
+	a.Equal(added, []string{"/options OPTIONS"})
+	srvmux.Get("/posts/{id:\\d+}", buildHandler(1)).
+		Post("/posts/{id:\\d+}", buildHandler(1))
+	a.Equal(added, []string{"/options OPTIONS", "/posts/{id:\\d+} GET", "/posts/{id:\\d+} POST"})
+
+	// 部分请求方法添加失败，不触发
+	added = added[:0]
+	a.Error(srvmux.Handle("/posts/{id:\\d+}", buildHandler(1), http.MethodPut, http.MethodGet))
+	a.Empty(added)
+}
+
+func TestMux_OnRouteAdded_concurrent(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	var count int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			srvmux.OnRouteAdded(func(string, []string) { atomic.AddInt32(&count, 1) })
+			srvmux.OnRouteRemoved(func(string, []string) {})
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		a.NotError(srvmux.Handle("/posts", h, http.MethodGet))
+		srvmux.Remove("/posts")
+	}
+	<-done
+
+	a.NotError(srvmux.Handle("/posts", h, http.MethodGet))
+	a.True(atomic.LoadInt32(&count) >= 100)
+}
+
+func TestDiffMethods(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(diffMethods([]string{"GET", "OPTIONS", "POST"}, []string{"OPTIONS"}), []string{"GET", "POST"})
+	a.Nil(diffMethods([]string{"GET"}, []string{"GET", "POST"}))
+	a.Equal(diffMethods([]string{"GET"}, nil), []string{"GET"})
+}
@@ -8,6 +8,8 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 )
 
 type optionsState int8
@@ -21,9 +23,24 @@ const (
 
 // Handlers 用于表示某节点下各个请求方法对应的处理函数。
 type Handlers struct {
+	// 保护 handlers、optionsAllow、optionsState、405 和 501 的处理函数、
+	// 处理时限和报文大小限制以及 route 中的名称和元数据，
+	// 这些值在处理请求的同时，可能会被添加或是删除路由项等操作修改。
+	mu sync.RWMutex
+
 	handlers     map[methodType]http.Handler // 请求方法及其对应的 http.Handler
 	optionsAllow string                      // 缓存的 OPTIONS 请求的 allow 报头内容。
 	optionsState optionsState                // OPTIONS 请求的处理方式
+
+	// 当前节点特有的 405 和 501 处理函数，为空表示由调用方决定如何处理。
+	methodNotAllowed http.Handler
+	notImplemented   http.Handler
+
+	// 当前节点的处理时限和报文大小限制，为 0 表示由调用方决定。
+	timeout     time.Duration
+	maxBodySize int64
+
+	route Route
 }
 
 // New 声明一个新的 Handlers 实例
@@ -32,6 +49,7 @@ func New(disableOptions bool) *Handlers {
 		handlers:     make(map[methodType]http.Handler, 4), // 大部分不会超过 4 条数据
 		optionsState: optionsStateDefault,
 	}
+	ret.route.hs = ret
 
 	if disableOptions {
 		ret.optionsState = optionsStateDisable
@@ -47,6 +65,9 @@ func New(disableOptions bool) *Handlers {
 
 // Add 添加一个处理函数
 func (hs *Handlers) Add(h http.Handler, methods ...string) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
 	if len(methods) == 0 {
 		methods = any
 	}
@@ -90,7 +111,7 @@ func (hs *Handlers) addSingle(h http.Handler, m methodType) error {
 }
 
 func (hs *Handlers) optionsServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Allow", hs.optionsAllow)
+	w.Header().Set("Allow", hs.Options())
 }
 
 func (hs *Handlers) getOptionsAllow() string {
@@ -104,6 +125,9 @@ func (hs *Handlers) getOptionsAllow() string {
 // Remove 移除某个请求方法对应的处理函数。
 // 返回值表示是否已经被清空。
 func (hs *Handlers) Remove(methods ...string) bool {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
 	if len(methods) == 0 {
 		methods = supported
 	}
@@ -117,13 +141,13 @@ func (hs *Handlers) Remove(methods ...string) bool {
 	}
 
 	// 删完了
-	if hs.Len() == 0 {
+	if len(hs.handlers) == 0 {
 		hs.optionsAllow = ""
 		return true
 	}
 
 	// 只有一个 OPTIONS 了，且未经外界强制修改，则将其也一并删除。
-	if hs.Len() == 1 &&
+	if len(hs.handlers) == 1 &&
 		hs.handlers[options] != nil &&
 		hs.optionsState == optionsStateDefault {
 		delete(hs.handlers, options)
@@ -139,6 +163,9 @@ func (hs *Handlers) Remove(methods ...string) bool {
 
 // SetAllow 设置 Options 请求头的 Allow 报头。
 func (hs *Handlers) SetAllow(optionsAllow string) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
 	if hs.optionsState == optionsStateDisable {
 		hs.handlers[options] = http.HandlerFunc(hs.optionsServeHTTP)
 	}
@@ -148,15 +175,77 @@ func (hs *Handlers) SetAllow(optionsAllow string) {
 
 // Handler 获取指定方法对应的处理函数
 func (hs *Handlers) Handler(method string) http.Handler {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
 	return hs.handlers[methodMap[method]]
 }
 
 // Options 获取当前支持的请求方法列表字符串
 func (hs *Handlers) Options() string {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
 	return hs.optionsAllow
 }
 
 // Len 获取当前支持请求方法数量
 func (hs *Handlers) Len() int {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
 	return len(hs.handlers)
 }
+
+// SetMethodNotAllowed 指定当前节点的 405 处理函数，为 nil 表示取消。
+func (hs *Handlers) SetMethodNotAllowed(h http.Handler) {
+	hs.mu.Lock()
+	hs.methodNotAllowed = h
+	hs.mu.Unlock()
+}
+
+// MethodNotAllowed 获取当前节点的 405 处理函数，未指定则返回 nil。
+func (hs *Handlers) MethodNotAllowed() http.Handler {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.methodNotAllowed
+}
+
+// SetNotImplemented 指定当前节点的 501 处理函数，为 nil 表示取消。
+func (hs *Handlers) SetNotImplemented(h http.Handler) {
+	hs.mu.Lock()
+	hs.notImplemented = h
+	hs.mu.Unlock()
+}
+
+// NotImplemented 获取当前节点的 501 处理函数，未指定则返回 nil。
+func (hs *Handlers) NotImplemented() http.Handler {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.notImplemented
+}
+
+// SetTimeout 指定当前节点的处理时限，为 0 表示取消。
+func (hs *Handlers) SetTimeout(timeout time.Duration) {
+	hs.mu.Lock()
+	hs.timeout = timeout
+	hs.mu.Unlock()
+}
+
+// Timeout 获取当前节点的处理时限，未指定则返回 0。
+func (hs *Handlers) Timeout() time.Duration {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.timeout
+}
+
+// SetMaxBodySize 指定当前节点的请求报文的最大值，为 0 表示取消。
+func (hs *Handlers) SetMaxBodySize(size int64) {
+	hs.mu.Lock()
+	hs.maxBodySize = size
+	hs.mu.Unlock()
+}
+
+// MaxBodySize 获取当前节点的请求报文的最大值，未指定则返回 0。
+func (hs *Handlers) MaxBodySize() int64 {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.maxBodySize
+}
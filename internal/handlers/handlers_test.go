@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/issue9/assert"
 )
@@ -119,3 +120,54 @@ func TestHandlers_optionsAllow(t *testing.T) {
 	a.NotError(hs.Add(optionsHandler, http.MethodOptions)) // 通过 Add() 再次显示指定
 	test("options")
 }
+
+func TestHandlers_ErrorHandlers(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	a.NotNil(hs)
+
+	a.Nil(hs.MethodNotAllowed()).Nil(hs.NotImplemented())
+
+	hs.SetMethodNotAllowed(getHandler)
+	hs.SetNotImplemented(optionsHandler)
+	a.NotNil(hs.MethodNotAllowed()).NotNil(hs.NotImplemented())
+
+	hs.SetMethodNotAllowed(nil)
+	hs.SetNotImplemented(nil)
+	a.Nil(hs.MethodNotAllowed()).Nil(hs.NotImplemented())
+}
+
+func TestHandlers_concurrent(t *testing.T) {
+	hs := New(false)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			hs.MethodNotAllowed()
+			hs.NotImplemented()
+			hs.Timeout()
+			hs.MaxBodySize()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		hs.SetMethodNotAllowed(getHandler)
+		hs.SetNotImplemented(getHandler)
+		hs.SetTimeout(time.Second)
+		hs.SetMaxBodySize(1024)
+	}
+	<-done
+}
+
+func TestHandlers_Limits(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	a.NotNil(hs)
+
+	a.Equal(hs.Timeout(), 0).Equal(hs.MaxBodySize(), 0)
+
+	hs.SetTimeout(time.Second)
+	hs.SetMaxBodySize(1024)
+	a.Equal(hs.Timeout(), time.Second).Equal(hs.MaxBodySize(), 1024)
+}
@@ -64,6 +64,19 @@ func init() {
 	makeOptionsStrings()
 }
 
+// Methods 返回当前支持的所有请求方法
+func Methods() []string {
+	ret := make([]string, len(supported))
+	copy(ret, supported)
+	return ret
+}
+
+// IsSupported 判断 method 是否为当前支持的请求方法
+func IsSupported(method string) bool {
+	_, found := methodMap[method]
+	return found
+}
+
 func makeOptionsStrings() {
 	methods := make([]string, 0, len(supported))
 	for i := methodType(0); i < max; i++ {
@@ -5,6 +5,7 @@
 package handlers
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/issue9/assert"
@@ -48,3 +49,13 @@ func TestOptionsStrings(t *testing.T) {
 	test(get+post+options+del+trace+head+patch, "DELETE, GET, HEAD, OPTIONS, PATCH, POST, TRACE")
 	test(max-1, "CONNECT, DELETE, GET, HEAD, OPTIONS, PATCH, POST, PUT, TRACE")
 }
+
+func TestMethods_IsSupported(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(len(Methods()), len(supported))
+	a.True(IsSupported(http.MethodGet))
+	a.True(IsSupported(http.MethodOptions))
+	a.False(IsSupported("FOO"))
+	a.False(IsSupported("get")) // 区分大小写
+}
@@ -0,0 +1,171 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Route 表示某一路由项的描述信息。
+//
+// 每个 Handlers 都包含一个 Route 实例，其生命周期与 Handlers 相同，
+// 所以在请求过程中传递 *Route 不会产生额外的内存分配。
+type Route struct {
+	hs      *Handlers
+	pattern string
+	params  []string
+	name    string
+
+	// 元数据，meta 为所有请求方法共用的，
+	// methodsMeta 为各个请求方法特有的，优先级高于 meta。
+	meta        map[string]interface{}
+	methodsMeta map[methodType]map[string]interface{}
+}
+
+// Pattern 路由项的完整匹配模式，比如 /posts/{id:\\d+}
+func (r *Route) Pattern() string {
+	return r.pattern
+}
+
+// Params 路由项中的参数名称，按在匹配模式中出现的顺序排列，没有参数时为空。
+func (r *Route) Params() []string {
+	return r.params
+}
+
+// Name 路由项的名称，若未命名，则返回空值。
+func (r *Route) Name() string {
+	r.hs.mu.RLock()
+	defer r.hs.mu.RUnlock()
+	return r.name
+}
+
+// Methods 路由项当前支持的请求方法，按字母顺序排列。
+//
+// 包含自动生成的 OPTIONS 请求方法。
+func (r *Route) Methods() []string {
+	r.hs.mu.RLock()
+	defer r.hs.mu.RUnlock()
+
+	methods := make([]string, 0, len(r.hs.handlers))
+	for method, typ := range methodMap {
+		if _, found := r.hs.handlers[typ]; found {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// Meta 获取请求方法 method 下名为 key 的元数据。
+//
+// 优先查找为 method 指定的元数据，找不到再查找所有请求方法共用的元数据。
+func (r *Route) Meta(method, key string) (interface{}, bool) {
+	r.hs.mu.RLock()
+	defer r.hs.mu.RUnlock()
+
+	if meta, found := r.methodsMeta[methodMap[method]]; found {
+		if val, found := meta[key]; found {
+			return val, true
+		}
+	}
+
+	val, found := r.meta[key]
+	return val, found
+}
+
+// Metas 获取请求方法 method 下的所有元数据。
+//
+// 返回的是合并之后的副本，修改它不会影响原有的数据。
+func (r *Route) Metas(method string) map[string]interface{} {
+	r.hs.mu.RLock()
+	defer r.hs.mu.RUnlock()
+
+	methodMeta := r.methodsMeta[methodMap[method]]
+
+	ret := make(map[string]interface{}, len(r.meta)+len(methodMeta))
+	for key, val := range r.meta {
+		ret[key] = val
+	}
+	for key, val := range methodMeta {
+		ret[key] = val
+	}
+
+	return ret
+}
+
+// Allow 路由项的 Allow 报头内容，即 OPTIONS 请求和 405 时输出的内容。
+func (r *Route) Allow() string {
+	return r.hs.Options()
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (r *Route) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Pattern string   `json:"pattern"`
+		Name    string   `json:"name,omitempty"`
+		Methods []string `json:"methods"`
+		Allow   string   `json:"allow,omitempty"`
+	}{
+		Pattern: r.pattern,
+		Name:    r.Name(),
+		Methods: r.Methods(),
+		Allow:   r.Allow(),
+	})
+}
+
+// Route 获取当前实例关联的路由项描述信息
+func (hs *Handlers) Route() *Route {
+	return &hs.route
+}
+
+// SetPattern 设置路由项的匹配模式以及其中的参数名称
+func (hs *Handlers) SetPattern(pattern string, params []string) {
+	hs.route.pattern = pattern
+	hs.route.params = params
+}
+
+// SetMeta 为请求方法 methods 指定元数据，methods 为空表示所有请求方法共用。
+func (hs *Handlers) SetMeta(key string, val interface{}, methods ...string) error {
+	for _, m := range methods {
+		if _, found := methodMap[m]; !found {
+			return fmt.Errorf("不支持的请求方法 %s", m)
+		}
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	r := &hs.route
+	if len(methods) == 0 {
+		if r.meta == nil {
+			r.meta = make(map[string]interface{}, 5)
+		}
+		r.meta[key] = val
+		return nil
+	}
+
+	if r.methodsMeta == nil {
+		r.methodsMeta = make(map[methodType]map[string]interface{}, len(methods))
+	}
+	for _, m := range methods {
+		typ := methodMap[m]
+		if r.methodsMeta[typ] == nil {
+			r.methodsMeta[typ] = make(map[string]interface{}, 5)
+		}
+		r.methodsMeta[typ][key] = val
+	}
+
+	return nil
+}
+
+// SetName 设置路由项的名称
+func (hs *Handlers) SetName(name string) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.route.name = name
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestRoute(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	a.NotNil(hs)
+
+	r := hs.Route()
+	a.NotNil(r).Equal(r, hs.Route()) // 始终返回同一个实例
+	a.Empty(r.Pattern()).Empty(r.Name())
+	a.Equal(r.Methods(), []string{http.MethodOptions})
+
+	hs.SetPattern("/posts/{id}", []string{"id"})
+	hs.SetName("post")
+	a.NotError(hs.Add(getHandler, http.MethodPost, http.MethodGet))
+	a.Equal(r.Pattern(), "/posts/{id}").
+		Equal(r.Params(), []string{"id"}).
+		Equal(r.Name(), "post").
+		Equal(r.Methods(), []string{http.MethodGet, http.MethodOptions, http.MethodPost})
+
+	a.Equal(r.Allow(), "GET, OPTIONS, POST")
+
+	hs.Remove(http.MethodPost)
+	a.Equal(r.Methods(), []string{http.MethodGet, http.MethodOptions})
+	a.Equal(r.Allow(), "GET, OPTIONS")
+
+	// 禁用 OPTIONS
+	hs = New(true)
+	a.NotError(hs.Add(getHandler, http.MethodGet))
+	a.Equal(hs.Route().Methods(), []string{http.MethodGet})
+}
+
+// 修改路由项的同时读取其信息，需要配合 -race 使用
+func TestRoute_concurrent(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	r := hs.Route()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			r.Name()
+			r.Methods()
+			hs.Handler(http.MethodGet)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		hs.SetName("post")
+		a.NotError(hs.Add(getHandler, http.MethodGet))
+		hs.Remove(http.MethodGet)
+	}
+	<-done
+}
+
+func TestRoute_MarshalJSON(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	hs.SetPattern("/posts/{id}", []string{"id"})
+	a.NotError(hs.Add(getHandler, http.MethodGet))
+
+	data, err := json.Marshal(hs.Route())
+	a.NotError(err).Equal(string(data), `{"pattern":"/posts/{id}","methods":["GET","OPTIONS"],"allow":"GET, OPTIONS"}`)
+
+	hs.SetName("post")
+	hs.SetAllow("GET")
+	data, err = json.Marshal(hs.Route())
+	a.NotError(err).Equal(string(data), `{"pattern":"/posts/{id}","name":"post","methods":["GET","OPTIONS"],"allow":"GET"}`)
+}
+
+func TestRoute_Meta(t *testing.T) {
+	a := assert.New(t)
+	hs := New(false)
+	r := hs.Route()
+
+	val, found := r.Meta(http.MethodGet, "owner")
+	a.False(found).Nil(val)
+	a.Empty(r.Metas(http.MethodGet))
+
+	a.NotError(hs.SetMeta("owner", "team1"))
+	a.NotError(hs.SetMeta("scope", "read", http.MethodGet, http.MethodHead))
+	a.NotError(hs.SetMeta("scope", "write", http.MethodPost))
+	a.NotError(hs.SetMeta("owner", "team2", http.MethodDelete))
+	a.Error(hs.SetMeta("owner", "team2", "FOO"))
+
+	val, found = r.Meta(http.MethodGet, "owner")
+	a.True(found).Equal(val, "team1")
+	val, found = r.Meta(http.MethodDelete, "owner")
+	a.True(found).Equal(val, "team2")
+	val, found = r.Meta(http.MethodGet, "scope")
+	a.True(found).Equal(val, "read")
+	val, found = r.Meta(http.MethodPost, "scope")
+	a.True(found).Equal(val, "write")
+	val, found = r.Meta(http.MethodPut, "scope")
+	a.False(found).Nil(val)
+	val, found = r.Meta("FOO", "owner") // 不支持的请求方法，只能获取共用的元数据
+	a.True(found).Equal(val, "team1")
+
+	a.Equal(r.Metas(http.MethodGet), map[string]interface{}{"owner": "team1", "scope": "read"})
+	a.Equal(r.Metas(http.MethodDelete), map[string]interface{}{"owner": "team2"})
+
+	metas := r.Metas(http.MethodGet)
+	metas["owner"] = "team3"
+	val, _ = r.Meta(http.MethodGet, "owner")
+	a.Equal(val, "team1")
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package pattern 提供了 urlgen、jsroutes 和 openapi 等生成代码或文档时共用的匹配模式处理函数。
+package pattern
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/issue9/mux/internal/tree"
+)
+
+// Path 将匹配模式转换成去掉正则表达式之后的路径模板，同时返回其中的参数，
+// 比如 /posts/{id:\d+}/{slug} 转换成 /posts/{id}/{slug}。
+//
+// 没有参数时，返回的参数为 nil。
+func Path(pattern string) (string, []*tree.Segment, error) {
+	segs, err := tree.Parse(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path := new(strings.Builder)
+	var params []*tree.Segment
+	for _, seg := range segs {
+		if !seg.IsParam {
+			path.WriteString(seg.Value)
+			continue
+		}
+
+		path.WriteString("{" + seg.Name + "}")
+		params = append(params, seg)
+	}
+
+	return path.String(), params, nil
+}
+
+// IsInt 是否为只匹配整数的正则表达式
+func IsInt(expr string) bool {
+	return expr == `\d+` || expr == `[0-9]+`
+}
+
+// ExportName 将路由项名称转换成大写开头的驼峰形式，比如 user-post 转换成 UserPost。
+func ExportName(name string) string {
+	var buf strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
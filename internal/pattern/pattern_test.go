@@ -0,0 +1,45 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package pattern
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestPath(t *testing.T) {
+	a := assert.New(t)
+
+	path, params, err := Path("/posts")
+	a.NotError(err).Equal(path, "/posts").Nil(params)
+
+	path, params, err = Path("/posts/{id:\\d+}/{path}")
+	a.NotError(err).Equal(path, "/posts/{id}/{path}")
+	a.Equal(len(params), 2)
+	a.Equal(params[0].Name, "id").Equal(params[0].Regexp, "\\d+")
+	a.Equal(params[1].Name, "path").True(params[1].Endpoint)
+
+	path, params, err = Path("/posts/{id")
+	a.Error(err).Empty(path).Nil(params)
+}
+
+func TestIsInt(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(IsInt("\\d+"))
+	a.True(IsInt("[0-9]+"))
+	a.False(IsInt("\\d*"))
+	a.False(IsInt(""))
+}
+
+func TestExportName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(ExportName("user-post"), "UserPost")
+	a.Equal(ExportName("user_post.list"), "UserPostList")
+	a.Equal(ExportName("getUser"), "GetUser")
+	a.Equal(ExportName("文章"), "文章")
+}
@@ -7,81 +7,309 @@ package tree
 import (
 	"fmt"
 	"io"
+	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/issue9/mux/internal/handlers"
 	"github.com/issue9/mux/params"
 )
 
+// Node 表示路由树中的节点，仅用于调试。
+type Node struct {
+	Pattern  string          `json:"pattern"`            // 当前节点的匹配内容，不包含父节点的内容
+	Type     string          `json:"type"`               // 节点类型，可以是 string、regexp 和 named
+	Endpoint bool            `json:"endpoint,omitempty"` // 是否为终点
+	Priority int             `json:"priority"`           // 节点的优先级，值越小越先被匹配
+	Route    *handlers.Route `json:"route,omitempty"`    // 节点对应的路由项，没有处理函数时为 nil
+	Children []*Node         `json:"children,omitempty"`
+}
+
+// Trace 表示某一路径在路由树中的匹配过程，仅用于调试。
+type Trace struct {
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+
+	// 最终的匹配结果，与 Mux.ServeHTTP 的状态码相对应，
+	// 200 表示能正常匹配，其它为匹配失败时的状态码。
+	Status int `json:"status,omitempty"`
+
+	Route  *handlers.Route `json:"route,omitempty"`  // 匹配的路由项，未匹配时为 nil
+	Params params.Params   `json:"params,omitempty"` // 路由参数
+	Steps  []*TraceStep    `json:"steps"`            // 匹配的每一步操作
+}
+
+// TraceStep 表示在匹配过程中对单个节点的比较结果。
+type TraceStep struct {
+	Depth   int    `json:"depth"`   // 节点的深度，从 0 开始
+	Pattern string `json:"pattern"` // 节点的匹配内容
+	Type    string `json:"type"`    // 节点类型
+	Path    string `json:"path"`    // 与节点进行比较的路径
+	Matched bool   `json:"matched"` // 是否匹配
+	Reason  string `json:"reason"`  // 匹配或是不匹配的原因
+}
+
+// Nodes 获取整棵树的节点信息
+func (tree *Tree) Nodes() []*Node {
+	return tree.node.nodes()
+}
+
+// Routes 获取所有的路由项，按匹配模式排序。
+func (tree *Tree) Routes() []*handlers.Route {
+	routes := make([]*handlers.Route, 0, 50)
+	routes = tree.node.routes(routes)
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern() < routes[j].Pattern()
+	})
+
+	return routes
+}
+
 // Print 向 w 输出树状结构
 func (tree *Tree) Print(w io.Writer) {
-	tree.print(w, 0)
+	PrintNodes(w, tree.Nodes())
 }
 
 // Trace 向 w 输出详细的节点匹配过程
 func (tree *Tree) Trace(w io.Writer, path string) {
-	params := make(params.Params, 10)
-	tree.trace(w, 0, path, params)
+	tree.Explain("", path).Print(w)
+}
+
+// Explain 获取 path 在路由树中的匹配过程。
+//
+// method 用于计算 Trace.Status，若为空，则不计算 Trace.Status 的值。
+func (tree *Tree) Explain(method, path string) *Trace {
+	t := &Trace{
+		Method: method,
+		Path:   path,
+		Steps:  make([]*TraceStep, 0, 10),
+	}
+
+	var ps params.Params
+	n := tree.node.explain(t, 0, path, &ps)
+	if n != nil {
+		t.Route = n.handlers.Route()
+		t.Params = ps
+	}
+
+	if method == "" {
+		return t
+	}
+
+	switch {
+	case n == nil:
+		t.Status = http.StatusNotFound
+	case n.handlers.Handler(method) != nil:
+		t.Status = http.StatusOK
+	case handlers.IsSupported(method):
+		t.Status = http.StatusMethodNotAllowed
+	default:
+		t.Status = http.StatusNotImplemented
+	}
+
+	return t
+}
+
+// PrintNodes 以缩进的方式向 w 输出 nodes 的内容。
+//
+// 有路由项的节点，会在节点之后输出完整的匹配模式、请求方法以及名称。
+func PrintNodes(w io.Writer, nodes []*Node) {
+	for _, n := range nodes {
+		n.print(w, 0)
+	}
+}
+
+func (n *Node) print(w io.Writer, deep int) {
+	fmt.Fprint(w, strings.Repeat(" ", deep*4), n.Pattern)
+
+	if n.Route != nil {
+		fmt.Fprint(w, "    => ", n.Route.Pattern(), " [", strings.Join(n.Route.Methods(), ", "), "]")
+		if name := n.Route.Name(); name != "" {
+			fmt.Fprint(w, " (", name, ")")
+		}
+	}
+	fmt.Fprintln(w)
+
+	for _, child := range n.Children {
+		child.print(w, deep+1)
+	}
 }
 
+// Print 向 w 输出匹配过程
+func (t *Trace) Print(w io.Writer) {
+	fmt.Fprintln(w, strings.TrimSpace(t.Method+" "+t.Path))
+
+	for _, step := range t.Steps {
+		state := "!matched"
+		if step.Matched {
+			state = "matched"
+		}
+
+		fmt.Fprintf(w, "%s%s---%s---%s(%s: %s)\n",
+			strings.Repeat(" ", step.Depth*4), step.Pattern, step.Type, step.Path, state, step.Reason)
+	}
+
+	if t.Route == nil {
+		fmt.Fprintln(w, "no route")
+	} else {
+		fmt.Fprint(w, "route: ", t.Route.Pattern(), " [", strings.Join(t.Route.Methods(), ", "), "]")
+		if len(t.Params) > 0 {
+			fmt.Fprint(w, " ", map[string]string(t.Params))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if t.Status > 0 {
+		fmt.Fprintln(w, "status:", t.Status)
+	}
+}
+
+// 将当前节点及其子节点中的路由项添加到 routes 中
+func (n *node) routes(routes []*handlers.Route) []*handlers.Route {
+	if n.handlers != nil && n.handlers.Len() > 0 {
+		routes = append(routes, n.handlers.Route())
+	}
+
+	for _, child := range n.children {
+		routes = child.routes(routes)
+	}
+
+	return routes
+}
+
+// 获取当前节点的所有子节点信息
+func (n *node) nodes() []*Node {
+	nodes := make([]*Node, 0, len(n.children))
+	for _, child := range n.children {
+		nn := &Node{
+			Pattern:  child.pattern,
+			Type:     child.nodeType.String(),
+			Endpoint: child.endpoint,
+			Priority: child.priority(),
+			Children: child.nodes(),
+		}
+
+		if child.handlers != nil && child.handlers.Len() > 0 {
+			nn.Route = child.handlers.Route()
+		}
+
+		nodes = append(nodes, nn)
+	}
+
+	return nodes
+}
+
+// 记录匹配过程的 node.match
+//
 // NOTE: 此函数与 node.match 是一样的，记得同步两边的代码。
-func (n *node) trace(w io.Writer, deep int, path string, params params.Params) *node {
+func (n *node) explain(t *Trace, deep int, path string, ps *params.Params) *node {
 	if len(n.indexes) > 0 && len(path) > 0 {
 		node := n.children[n.indexes[path[0]]]
-		fmt.Fprint(w, strings.Repeat(" ", deep*4), node.pattern, "---", node.nodeType, "---", path)
-
 		if node == nil {
-			fmt.Fprintln(w, "(!matched)")
 			goto LOOP
 		}
 
-		matched, newPath := node.matchCurrent(path, params)
+		matched, newPath := node.explainCurrent(t, deep, path, ps)
 		if !matched {
-			fmt.Fprintln(w, "(!matched)")
 			goto LOOP
 		}
 
-		fmt.Fprintln(w, "(continue)")
-		if nn := node.match(newPath, params); nn != nil {
+		if nn := node.explain(t, deep+1, newPath, ps); nn != nil {
 			return nn
 		}
+		t.backtrack(node, deep, newPath)
 	}
 
 LOOP:
-	// 即使 path 为空，也有可能子节点正好可以匹配空的内容。
-	// 比如 /posts/{path:\\w*} 后面的 path 即为空节点。所以此处不判断 len(path)
 	for i := len(n.indexes); i < len(n.children); i++ {
 		node := n.children[i]
-		fmt.Fprint(w, strings.Repeat(" ", deep*4), node.pattern, "---", node.nodeType, "---", path)
-		matched, newPath := node.matchCurrent(path, params)
+		matched, newPath := node.explainCurrent(t, deep, path, ps)
 		if !matched {
-			fmt.Fprintln(w, "(!matched)")
 			continue
 		}
 
-		fmt.Fprintln(w, "(continue)")
-		if nn := node.trace(w, deep+1, newPath, params); nn != nil {
+		if nn := node.explain(t, deep+1, newPath, ps); nn != nil {
 			return nn
 		}
+		t.backtrack(node, deep, newPath)
+
+		delete(*ps, n.name)
 	} // end for
 
 	if len(path) == 0 {
 		if n.handlers == nil || n.handlers.Len() == 0 {
 			return nil
 		}
-
-		fmt.Fprintln(w, strings.Repeat(" ", (deep-1)*4), n.pattern, "---", n.nodeType, "---", path, "(matched)")
 		return n
 	}
 
 	return nil
 }
 
-// 向 w 输出节点的树状结构
-func (n *node) print(w io.Writer, deep int) {
-	fmt.Fprintln(w, strings.Repeat(" ", deep*4), n.pattern)
+// 记录对当前节点的匹配结果
+func (n *node) explainCurrent(t *Trace, deep int, path string, ps *params.Params) (bool, string) {
+	matched, newPath := n.matchCurrent(path, ps)
 
-	for _, child := range n.children {
-		child.print(w, deep+1)
+	step := &TraceStep{
+		Depth:   deep,
+		Pattern: n.pattern,
+		Type:    n.nodeType.String(),
+		Path:    path,
+		Matched: matched,
+	}
+
+	switch {
+	case !matched:
+		step.Reason = n.mismatch(path)
+	case len(newPath) > 0:
+		step.Reason = fmt.Sprintf("剩余路径 %s 交由子节点匹配", newPath)
+	case n.handlers == nil || n.handlers.Len() == 0:
+		step.Reason = "路径已经匹配完，但节点没有处理函数"
+	default:
+		step.Reason = "路径已经匹配完"
+	}
+
+	t.Steps = append(t.Steps, step)
+	return matched, newPath
+}
+
+// 节点本身匹配，但是其子节点无法匹配剩余的路径，需要回退到上一级继续匹配。
+func (t *Trace) backtrack(n *node, deep int, path string) {
+	// 匹配完且没有处理函数的情况，已经在 node.explainCurrent 中说明
+	if len(path) == 0 && len(n.children) == 0 {
+		return
+	}
+
+	reason := "子节点无法匹配剩余路径，回退"
+	if len(n.children) == 0 {
+		reason = "没有子节点可以匹配剩余路径，回退"
+	}
+
+	t.Steps = append(t.Steps, &TraceStep{
+		Depth:   deep,
+		Pattern: n.pattern,
+		Type:    n.nodeType.String(),
+		Path:    path,
+		Matched: false,
+		Reason:  reason,
+	})
+}
+
+// 获取 path 与当前节点不匹配的原因
+func (n *node) mismatch(path string) string {
+	switch n.nodeType {
+	case nodeTypeString:
+		return fmt.Sprintf("路径不以 %s 开头", n.pattern)
+	case nodeTypeNamed:
+		if strings.Index(path, n.suffix) == 0 {
+			return fmt.Sprintf("参数 %s 的值不能为空", n.name)
+		}
+		return fmt.Sprintf("路径中不包含 %s", n.suffix)
+	case nodeTypeRegexp:
+		return fmt.Sprintf("路径的起始部分与正则表达式 %s 不匹配", n.expr.String())
+	default:
+		return "未知的节点类型"
 	}
 }
 
@@ -99,7 +327,7 @@ func (n *node) len() int {
 	return cnt
 }
 
-// 仅上面的 trace 用到
+// 用于调试信息的输出
 func (t nodeType) String() string {
 	switch t {
 	case nodeTypeNamed:
@@ -5,8 +5,10 @@
 package tree
 
 import (
+	"bytes"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/issue9/assert"
@@ -22,3 +24,100 @@ func TestTree_Trace(t *testing.T) {
 	//tree.Trace(os.Stdout, "/")
 	tree.Trace(os.Stdout, "/posts/1.html/author/profile")
 }
+
+func TestTree_Nodes(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	a.NotError(tree.Add("/posts/{id:\\d+}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts/{id}/author", buildHandler(1), http.MethodGet))
+
+	nodes := tree.Nodes()
+	a.Equal(len(nodes), 1)
+	a.Equal(nodes[0].Pattern, "/posts/").
+		Equal(nodes[0].Type, "string").
+		Nil(nodes[0].Route)
+
+	children := nodes[0].Children
+	a.Equal(len(children), 2)
+	a.Equal(children[0].Pattern, "{id:\\d+}").
+		Equal(children[0].Type, "regexp").
+		True(children[0].Endpoint).
+		NotNil(children[0].Route).
+		Equal(children[0].Route.Pattern(), "/posts/{id:\\d+}")
+	a.Equal(children[1].Pattern, "{id}/author").
+		Equal(children[1].Type, "named").
+		False(children[1].Endpoint).
+		NotNil(children[1].Route).
+		Equal(children[1].Route.Pattern(), "/posts/{id}/author")
+
+	w := new(bytes.Buffer)
+	tree.Print(w)
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	a.Equal(len(lines), 3)
+	a.Equal(lines[0], "/posts/")
+	a.Equal(lines[1], `    {id:\d+}    => /posts/{id:\d+} [GET, OPTIONS]`)
+}
+
+func TestTree_Explain(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	a.NotError(tree.Add("/posts/{id:\\d+}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts/{id}/author", buildHandler(1), http.MethodGet))
+
+	trace := tree.Explain(http.MethodGet, "/posts/1")
+	a.Equal(trace.Status, http.StatusOK).
+		Equal(trace.Route.Pattern(), "/posts/{id:\\d+}").
+		Equal(trace.Params, map[string]string{"id": "1"})
+	a.Equal(len(trace.Steps), 2)
+	a.True(trace.Steps[0].Matched).Equal(trace.Steps[0].Depth, 0)
+	a.True(trace.Steps[1].Matched).Equal(trace.Steps[1].Depth, 1)
+
+	// 正则不匹配，回退到命名参数
+	trace = tree.Explain(http.MethodGet, "/posts/abc/author")
+	a.Equal(trace.Status, http.StatusOK).
+		Equal(trace.Route.Pattern(), "/posts/{id}/author").
+		Equal(trace.Params, map[string]string{"id": "abc"})
+	a.Equal(len(trace.Steps), 3)
+	a.False(trace.Steps[1].Matched).
+		Equal(trace.Steps[1].Type, "regexp").
+		NotEmpty(trace.Steps[1].Reason)
+
+	// 回退
+	trace = tree.Explain(http.MethodGet, "/posts/1/author/x")
+	a.Equal(trace.Status, http.StatusNotFound).Nil(trace.Route).Nil(trace.Params)
+	last := trace.Steps[len(trace.Steps)-1]
+	a.False(last.Matched).Equal(last.Pattern, "/posts/")
+
+	trace = tree.Explain(http.MethodPost, "/posts/1")
+	a.Equal(trace.Status, http.StatusMethodNotAllowed).NotNil(trace.Route)
+
+	trace = tree.Explain("FOO", "/posts/1")
+	a.Equal(trace.Status, http.StatusNotImplemented).NotNil(trace.Route)
+
+	trace = tree.Explain("", "/posts/1")
+	a.Equal(trace.Status, 0).NotNil(trace.Route)
+
+	w := new(bytes.Buffer)
+	tree.Explain(http.MethodGet, "/posts/1").Print(w)
+	a.True(strings.HasPrefix(w.String(), "GET /posts/1\n"))
+	a.True(strings.HasSuffix(w.String(), "status: 200\n"))
+}
+
+func TestTree_Routes(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	a.Empty(tree.Routes())
+
+	a.NotError(tree.Add("/posts/{id}/author", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts/{id:\\d+}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/", buildHandler(1), http.MethodGet))
+
+	routes := tree.Routes()
+	a.Equal(len(routes), 3)
+	a.Equal(routes[0].Pattern(), "/").
+		Equal(routes[1].Pattern(), "/posts/{id:\\d+}").
+		Equal(routes[2].Pattern(), "/posts/{id}/author")
+}
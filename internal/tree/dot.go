@@ -0,0 +1,85 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 各类节点在 DOT 中的形状
+var dotShapes = map[string]string{
+	"string": "box",
+	"regexp": "hexagon",
+	"named":  "ellipse",
+}
+
+// 转义 DOT 字符串中的特殊字符
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Dot 向 w 输出 Graphviz 的 DOT 格式的树状结构。
+func (tree *Tree) Dot(w io.Writer) {
+	PrintDot(w, tree.Nodes())
+}
+
+// PrintDot 以 Graphviz 的 DOT 格式向 w 输出 nodes 的内容。
+//
+// 节点的形状表示节点的类型：字符串为 box，正则为 hexagon，命名参数为 ellipse；
+// 虚线边框表示终点节点；有路由项的节点会被填充颜色，并输出完整的匹配模式、
+// 请求方法和名称。
+//
+// 节点的 ID 按深度优先的顺序生成，相同的路由项总是生成相同的内容，方便比较差异。
+func PrintDot(w io.Writer, nodes []*Node) {
+	fmt.Fprintln(w, "digraph mux {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [fontname=\"monospace\"];")
+	fmt.Fprintln(w, "\tn0 [label=\"\", shape=point];")
+
+	id := 0
+	for _, n := range nodes {
+		n.dot(w, 0, &id)
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// parent 为父节点的 ID，id 为当前已经使用的最大 ID
+func (n *Node) dot(w io.Writer, parent int, id *int) {
+	*id++
+	curr := *id
+
+	label := fmt.Sprintf("%s\n%s, priority=%d", n.Pattern, n.Type, n.Priority)
+	if n.Route != nil {
+		label += fmt.Sprintf("\n%s\n[%s]", n.Route.Pattern(), strings.Join(n.Route.Methods(), ", "))
+		if name := n.Route.Name(); name != "" {
+			label += "\nname=" + name
+		}
+	}
+
+	attrs := []string{
+		fmt.Sprintf("label=\"%s\"", dotReplacer.Replace(label)),
+		"shape=" + dotShapes[n.Type],
+	}
+
+	var styles []string
+	if n.Endpoint {
+		styles = append(styles, "dashed")
+	}
+	if n.Route != nil {
+		styles = append(styles, "filled")
+		attrs = append(attrs, "fillcolor=\"#e0f0ff\"")
+	}
+	if len(styles) > 0 {
+		attrs = append(attrs, fmt.Sprintf("style=\"%s\"", strings.Join(styles, ",")))
+	}
+
+	fmt.Fprintf(w, "\tn%d [%s];\n", curr, strings.Join(attrs, ", "))
+	fmt.Fprintf(w, "\tn%d -> n%d;\n", parent, curr)
+
+	for _, child := range n.Children {
+		child.dot(w, curr, id)
+	}
+}
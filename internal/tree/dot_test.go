@@ -0,0 +1,46 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package tree
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestTree_Dot(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	w := new(bytes.Buffer)
+	tree.Dot(w)
+	a.Equal(w.String(), `digraph mux {
+	rankdir=LR;
+	node [fontname="monospace"];
+	n0 [label="", shape=point];
+}
+`)
+
+	a.NotError(tree.Add("/posts/{id:\\d+}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts/{id}/author", buildHandler(1), http.MethodGet))
+	tree.Find("/posts/{id:\\d+}").SetName("post")
+
+	w.Reset()
+	tree.Dot(w)
+	a.Equal(w.String(), `digraph mux {
+	rankdir=LR;
+	node [fontname="monospace"];
+	n0 [label="", shape=point];
+	n1 [label="/posts/\nstring, priority=1", shape=box];
+	n0 -> n1;
+	n2 [label="{id:\\d+}\nregexp, priority=11\n/posts/{id:\\d+}\n[GET, OPTIONS]\nname=post", shape=hexagon, fillcolor="#e0f0ff", style="dashed,filled"];
+	n1 -> n2;
+	n3 [label="{id}/author\nnamed, priority=20\n/posts/{id}/author\n[GET, OPTIONS]", shape=ellipse, fillcolor="#e0f0ff", style="filled"];
+	n1 -> n3;
+}
+`)
+}
@@ -216,20 +216,22 @@ func (n *node) clean(prefix string) {
 
 // 从子节点中查找与当前路径匹配的节点，若找不到，则返回 nil。
 //
-// NOTE: 此函数与 node.trace 是一样的，记得同步两边的代码。
-func (n *node) match(path string, params params.Params) *node {
+// ps 中的参数仅在有需要时才会被初始化。
+//
+// NOTE: 此函数与 node.explain 是一样的，记得同步两边的代码。
+func (n *node) match(path string, ps *params.Params) *node {
 	if len(n.indexes) > 0 && len(path) > 0 {
 		node := n.children[n.indexes[path[0]]]
 		if node == nil {
 			goto LOOP
 		}
 
-		matched, newPath := node.matchCurrent(path, params)
+		matched, newPath := node.matchCurrent(path, ps)
 		if !matched {
 			goto LOOP
 		}
 
-		if nn := node.match(newPath, params); nn != nil {
+		if nn := node.match(newPath, ps); nn != nil {
 			return nn
 		}
 	}
@@ -239,17 +241,17 @@ LOOP:
 	// 比如 /posts/{path:\\w*} 后面的 path 即为空节点。所以此处不判断 len(path)
 	for i := len(n.indexes); i < len(n.children); i++ {
 		node := n.children[i]
-		matched, newPath := node.matchCurrent(path, params)
+		matched, newPath := node.matchCurrent(path, ps)
 		if !matched {
 			continue
 		}
 
-		if nn := node.match(newPath, params); nn != nil {
+		if nn := node.match(newPath, ps); nn != nil {
 			return nn
 		}
 
 		// 不匹配，则删除写入的参数
-		delete(params, n.name)
+		delete(*ps, n.name)
 	} // end for
 
 	// 没有子节点匹配，且 len(path)==0，可以判定与当前节点匹配
@@ -265,7 +267,7 @@ LOOP:
 	return nil
 }
 
-func (n *node) matchCurrent(path string, params params.Params) (bool, string) {
+func (n *node) matchCurrent(path string, ps *params.Params) (bool, string) {
 	switch n.nodeType {
 	case nodeTypeString:
 		if strings.HasPrefix(path, n.pattern) {
@@ -273,13 +275,13 @@ func (n *node) matchCurrent(path string, params params.Params) (bool, string) {
 		}
 	case nodeTypeNamed:
 		if n.endpoint {
-			params[n.name] = path
+			setParam(ps, n.name, path)
 			return true, path[:0]
 		}
 
 		// 为零说明前面没有命名参数，肯定不能与当前内容匹配
 		if index := strings.Index(path, n.suffix); index > 0 {
-			params[n.name] = path[:index]
+			setParam(ps, n.name, path[:index])
 			return true, path[index+len(n.suffix):]
 		}
 	case nodeTypeRegexp:
@@ -288,13 +290,21 @@ func (n *node) matchCurrent(path string, params params.Params) (bool, string) {
 			return false, path
 		}
 
-		params[n.name] = path[:locs[3]]
+		setParam(ps, n.name, path[:locs[3]])
 		return true, path[locs[1]:]
 	}
 
 	return false, path
 }
 
+// 向 ps 写入参数，*ps 为 nil 时会先初始化。
+func setParam(ps *params.Params, key, val string) {
+	if *ps == nil {
+		*ps = make(params.Params, 5)
+	}
+	(*ps)[key] = val
+}
+
 // URL 根据参数生成地址
 func (n *node) url(params map[string]string) (string, error) {
 	nodes := make([]*node, 0, 5)
@@ -0,0 +1,94 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package tree
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Prefix 用于判断路径是否以指定的前缀开头，前缀的语法与路由项相同，可以包含参数。
+type Prefix struct {
+	pattern string
+	segs    []*prefixSegment
+}
+
+type prefixSegment struct {
+	value string         // 普通字符串的内容
+	named bool           // 是否为命名参数
+	expr  *regexp.Regexp // 正则参数的表达式
+}
+
+// NewPrefix 声明 Prefix 实例，pattern 为空时匹配所有的路径。
+func NewPrefix(pattern string) (*Prefix, error) {
+	p := &Prefix{pattern: pattern}
+	if pattern == "" {
+		return p, nil
+	}
+
+	segs, err := Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	p.segs = make([]*prefixSegment, 0, len(segs))
+	for _, seg := range segs {
+		switch {
+		case !seg.IsParam:
+			p.segs = append(p.segs, &prefixSegment{value: seg.Value})
+		case seg.Regexp == "":
+			p.segs = append(p.segs, &prefixSegment{named: true})
+		default:
+			expr, err := regexp.Compile("(?:" + seg.Regexp + ")")
+			if err != nil {
+				return nil, err
+			}
+			p.segs = append(p.segs, &prefixSegment{expr: expr})
+		}
+	}
+
+	return p, nil
+}
+
+// Pattern 前缀的内容
+func (p *Prefix) Pattern() string {
+	return p.pattern
+}
+
+// Match path 是否以当前前缀开头。
+//
+// 前缀之后只能是路径的结尾或是 /，所以 /api 不会匹配 /apiv2；
+// 命名参数匹配至其后的普通字符串为止，若为最后一段，则匹配至下一个 / 为止；
+// 正则参数需要从当前位置开始匹配。
+func (p *Prefix) Match(path string) bool {
+	for i, seg := range p.segs {
+		switch {
+		case seg.expr != nil:
+			loc := seg.expr.FindStringIndex(path)
+			if loc == nil || loc[0] != 0 || loc[1] == 0 {
+				return false
+			}
+			path = path[loc[1]:]
+		case seg.named:
+			var index int
+			if i+1 < len(p.segs) {
+				index = strings.Index(path, p.segs[i+1].value)
+			} else if index = strings.IndexByte(path, '/'); index < 0 {
+				index = len(path)
+			}
+			if index <= 0 { // 命名参数不能为空
+				return false
+			}
+			path = path[index:]
+		default:
+			if !strings.HasPrefix(path, seg.value) {
+				return false
+			}
+			path = path[len(seg.value):]
+		}
+	}
+
+	return path == "" || path[0] == '/' || strings.HasSuffix(p.pattern, "/")
+}
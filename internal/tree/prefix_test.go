@@ -0,0 +1,53 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package tree
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestPrefix_Match(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(pattern, path string, match bool) {
+		p, err := NewPrefix(pattern)
+		a.NotError(err).NotNil(p)
+		a.Equal(p.Match(path), match, "%s 与 %s 的匹配结果不正确", pattern, path)
+	}
+
+	test("", "/", true)
+	test("", "/api", true)
+
+	test("/api", "/api", true)
+	test("/api", "/api/", true)
+	test("/api", "/api/users", true)
+	test("/api", "/apiv2", false)
+	test("/api", "/apiv2/users", false)
+	test("/api", "/ap", false)
+	test("/api/", "/api/users", true)
+	test("/api/", "/api", false)
+
+	test("/p/{version:\\d+}", "/p/1", true)
+	test("/p/{version:\\d+}", "/p/12/users", true)
+	test("/p/{version:\\d+}", "/p/", false)
+	test("/p/{version:\\d+}", "/p/abc", false)
+	test("/p/{version:\\d+}", "/p/1abc", false)
+	test("/p/{version:\\d+}", "/users/p/1", false)
+
+	test("/p/{version}", "/p/v1", true)
+	test("/p/{version}", "/p/v1/users", true)
+	test("/p/{version}", "/p/", false)
+	test("/p/{version}/users", "/p/v1/users/1", true)
+	test("/p/{version}/users", "/p/v1/posts", false)
+	test("/p/{version}/users", "/p/v1/usersv2", false)
+
+	p, err := NewPrefix("/p/{version")
+	a.Error(err).Nil(p)
+
+	p, err = NewPrefix("/p/{version:[}")
+	a.Error(err).Nil(p)
+}
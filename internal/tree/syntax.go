@@ -187,3 +187,55 @@ func split(str string) ([]string, error) {
 
 	return ss, nil
 }
+
+// Segment 表示路由项中的一段内容，可以是普通字符串，也可以是参数。
+type Segment struct {
+	// 普通字符串的内容，参数时为空。
+	Value string
+
+	// 是否为参数
+	IsParam bool
+
+	// 参数名称，仅参数时有值。
+	Name string
+
+	// 参数的正则表达式，命名参数为空。
+	Regexp string
+
+	// 是否为路由项的最后一段内容。
+	// 处于最后的命名参数会匹配之后的所有字符，包括 /。
+	Endpoint bool
+}
+
+// Parse 将路由项解析成 Segment 列表。
+//
+// 语法检测与 Tree.Add 相同，若有语法错误，则返回错误信息。
+func Parse(pattern string) ([]*Segment, error) {
+	ss, err := split(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	segs := make([]*Segment, 0, len(ss)*2)
+	for _, s := range ss {
+		if s[0] != nameStart {
+			segs = append(segs, &Segment{Value: s})
+			continue
+		}
+
+		end := strings.IndexByte(s, nameEnd)
+		seg := &Segment{IsParam: true, Name: s[1:end]}
+		if index := strings.IndexByte(seg.Name, regexpSeparator); index >= 0 {
+			seg.Regexp = seg.Name[index+1:]
+			seg.Name = seg.Name[:index]
+		}
+		segs = append(segs, seg)
+
+		if end+1 < len(s) {
+			segs = append(segs, &Segment{Value: s[end+1:]})
+		}
+	}
+	segs[len(segs)-1].Endpoint = true
+
+	return segs, nil
+}
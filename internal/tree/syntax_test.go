@@ -100,3 +100,33 @@ func TestSplit(t *testing.T) {
 	test("/posts/{id}/{author", true)
 	test("/posts/}/author", true)
 }
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+
+	segs, err := Parse("/posts/{id:\\d+}/author/{name}.html")
+	a.NotError(err).Equal(segs, []*Segment{
+		{Value: "/posts/"},
+		{IsParam: true, Name: "id", Regexp: "\\d+"},
+		{Value: "/author/"},
+		{IsParam: true, Name: "name"},
+		{Value: ".html", Endpoint: true},
+	})
+
+	segs, err = Parse("/assets/{type:\\w+}/{path}")
+	a.NotError(err).Equal(segs, []*Segment{
+		{Value: "/assets/"},
+		{IsParam: true, Name: "type", Regexp: "\\w+"},
+		{Value: "/"},
+		{IsParam: true, Name: "path", Endpoint: true},
+	})
+
+	segs, err = Parse("/posts")
+	a.NotError(err).Equal(segs, []*Segment{{Value: "/posts", Endpoint: true}})
+
+	segs, err = Parse("/posts/{id")
+	a.Error(err).Nil(segs)
+
+	segs, err = Parse("")
+	a.Error(err).Nil(segs)
+}
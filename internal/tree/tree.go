@@ -48,16 +48,12 @@ func New(disableOptions bool) *Tree {
 //
 // methods 可以为空，表示添加除 OPTIONS 之外所有支持的请求方法。
 func (tree *Tree) Add(pattern string, h http.Handler, methods ...string) error {
-	n, err := tree.getNode(pattern)
+	hs, err := tree.Handlers(pattern)
 	if err != nil {
 		return err
 	}
 
-	if n.handlers == nil {
-		n.handlers = handlers.New(tree.disableOptions)
-	}
-
-	return n.handlers.Add(h, methods...)
+	return hs.Add(h, methods...)
 }
 
 // Clean 清除路由项
@@ -102,17 +98,52 @@ func (tree *Tree) getNode(pattern string) (*node, error) {
 // SetAllow 设置指定节点的 allow 报头。
 // 若节点不存在，则会自动生成该节点。
 func (tree *Tree) SetAllow(pattern, allow string) error {
-	n, err := tree.getNode(pattern)
+	hs, err := tree.Handlers(pattern)
 	if err != nil {
 		return err
 	}
 
+	hs.SetAllow(allow)
+	return nil
+}
+
+// Handlers 获取指定节点的 handlers.Handlers 实例。
+// 若节点不存在，则会自动生成该节点。
+func (tree *Tree) Handlers(pattern string) (*handlers.Handlers, error) {
+	n, err := tree.getNode(pattern)
+	if err != nil {
+		return nil, err
+	}
+
 	if n.handlers == nil {
 		n.handlers = handlers.New(tree.disableOptions)
+		n.handlers.SetPattern(pattern, paramNames(pattern))
 	}
 
-	n.handlers.SetAllow(allow)
-	return nil
+	return n.handlers, nil
+}
+
+// 获取 pattern 中的参数名称，pattern 必须是合法的。
+func paramNames(pattern string) []string {
+	segs, _ := Parse(pattern)
+
+	var names []string
+	for _, seg := range segs {
+		if seg.IsParam {
+			names = append(names, seg.Name)
+		}
+	}
+	return names
+}
+
+// Find 查找与 pattern 完全相同的路由项，若不存在，则返回 nil。
+func (tree *Tree) Find(pattern string) *handlers.Handlers {
+	n := tree.find(pattern)
+	if n == nil {
+		return nil
+	}
+
+	return n.handlers
 }
 
 // URL 根据参数生成地址。
@@ -129,11 +160,30 @@ func (tree *Tree) URL(pattern string, params map[string]string) (string, error)
 // Handler 找到与当前内容匹配的 handlers.Handlers 实例。
 func (tree *Tree) Handler(path string) (*handlers.Handlers, params.Params) {
 	ps := make(params.Params, 5)
+	return tree.handler(path, &ps)
+}
+
+// Route 功能与 Handler 相同，但仅在路由中有参数时才会初始化返回的 params.Params，
+// 否则返回 nil，可以减少一次内存分配。
+func (tree *Tree) Route(path string) (*handlers.Handlers, params.Params) {
+	var ps params.Params
+	return tree.handler(path, &ps)
+}
+
+// RouteParams 功能与 Route 相同，但路由参数会写入由调用方提供的 ps 中。
+//
+// ps 不能为 nil，调用方可以在栈上分配 ps，以减少内存分配。
+func (tree *Tree) RouteParams(path string, ps params.Params) *handlers.Handlers {
+	hs, _ := tree.handler(path, &ps)
+	return hs
+}
+
+func (tree *Tree) handler(path string, ps *params.Params) (*handlers.Handlers, params.Params) {
 	node := tree.match(path, ps)
 
 	if node == nil || node.handlers == nil || node.handlers.Len() == 0 {
 		return nil, nil
 	}
 
-	return node.handlers, ps
+	return node.handlers, *ps
 }
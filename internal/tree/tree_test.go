@@ -252,3 +252,57 @@ func TestTree_SetAllow(t *testing.T) {
 	tree.Remove("/options")
 	a.Equal(n.handlers.Options(), "")
 }
+
+func TestTree_Handlers(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	// 不存在的节点，会自动创建
+	hs, err := tree.Handlers("/handlers")
+	a.NotError(err).NotNil(hs)
+	a.Equal(hs.Len(), 1) // 自动生成的 OPTIONS
+
+	a.NotError(tree.Add("/handlers", buildHandler(1), http.MethodGet))
+	hs1, err := tree.Handlers("/handlers")
+	a.NotError(err).Equal(hs1, hs)
+
+	// 语法错误
+	hs, err = tree.Handlers("/handlers/{id")
+	a.Error(err).Nil(hs)
+}
+
+func TestTree_Find(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	a.NotError(tree.Add("/posts/{id}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts/{id}/author", buildHandler(1), http.MethodGet))
+
+	hs := tree.Find("/posts/{id}/author")
+	a.NotNil(hs).Equal(hs.Route().Pattern(), "/posts/{id}/author")
+	hs = tree.Find("/posts/{id}")
+	a.NotNil(hs).Equal(hs.Route().Pattern(), "/posts/{id}")
+
+	a.Nil(tree.Find("/posts/"))       // 存在节点，但没有处理函数
+	a.Nil(tree.Find("/not-exists"))   // 不存在
+	a.Nil(tree.Find("/posts/{id}/a")) // 不存在
+}
+
+func TestTree_Route(t *testing.T) {
+	a := assert.New(t)
+	tree := New(false)
+
+	a.NotError(tree.Add("/posts/{id}", buildHandler(1), http.MethodGet))
+	a.NotError(tree.Add("/posts", buildHandler(1), http.MethodGet))
+
+	hs, ps := tree.Route("/posts")
+	a.NotNil(hs).Nil(ps)
+	a.Equal(hs.Route().Pattern(), "/posts")
+
+	hs, ps = tree.Route("/posts/1")
+	a.NotNil(hs).Equal(ps, map[string]string{"id": "1"})
+	a.Equal(hs.Route().Pattern(), "/posts/{id}")
+
+	hs, ps = tree.Route("/not-exists")
+	a.Nil(hs).Nil(ps)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/issue9/mux/params"
+)
+
+// JSON 将 f 转换成处理 JSON 请求的 HandlerFuncE，可以通过 HandleE 等方法注册：
+//  type getPost struct {
+//      ID     int64  `param:"id"`
+//      Fields string `query:"fields"`
+//  }
+//
+//  m.Resource("/posts/{id:\\d+}").GetE(mux.JSON(func(ctx context.Context, req getPost) (*Post, error) {
+//      ...
+//  }))
+//
+// 处理流程如下：
+//  - 若请求包含报文，则以 JSON 格式解码到 Req，报文格式不正确时返回 400，
+//    Content-Type 不是 JSON 时返回 415；
+//  - 若 Req 为结构体，则通过 params.Bind 写入路由参数，通过 params.BindQuery
+//    写入查询参数，出错时返回 params.BindError；
+//  - 调用 f，其返回的错误原样返回；
+//  - 将 f 的返回值以 JSON 格式输出，状态码为 200，与请求方法无关；
+//    若 Resp 为 struct{}，则输出 204，且不输出报文。
+// 所有的错误均交由 Mux.SetErrorHandler 指定的函数处理。
+//
+// 需要输出其它状态码的，比如创建资源时的 201，可以使用 JSONStatus。
+func JSON[Req, Resp any](f func(context.Context, Req) (Resp, error)) HandlerFuncE {
+	return JSONStatus(http.StatusOK, f)
+}
+
+// JSONStatus 功能与 JSON 相同，但是成功时以 status 作为状态码输出：
+//  m.Resource("/posts").PostE(mux.JSONStatus(http.StatusCreated, createPost))
+//
+// 若 Resp 为 struct{}，则依然输出 204，且不输出报文。
+func JSONStatus[Req, Resp any](status int, f func(context.Context, Req) (Resp, error)) HandlerFuncE {
+	_, noContent := interface{}(*new(Resp)).(struct{})
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var req Req
+		if err := decodeJSON(r, &req); err != nil {
+			return err
+		}
+
+		if reflect.TypeOf(&req).Elem().Kind() == reflect.Struct {
+			if err := params.Bind(r, &req); err != nil {
+				return err
+			}
+
+			if err := params.BindQuery(r, &req); err != nil {
+				return err
+			}
+		}
+
+		resp, err := f(r.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		if noContent {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", jsonContentType)
+		w.WriteHeader(status)
+		_, err = w.Write(data)
+		return err
+	}
+}
+
+// 将请求的报文解码到 v 中，没有报文时不作任何处理。
+func decodeJSON(r *http.Request, v interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mimetype, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mimetype != jsonContentType && !strings.HasSuffix(mimetype, "+json")) {
+			return NewStatusError(http.StatusUnsupportedMediaType, fmt.Errorf("不支持的报文类型 %s", ct))
+		}
+	}
+
+	err := json.NewDecoder(r.Body).Decode(v)
+	switch {
+	case errors.Is(err, io.EOF): // 报文为空
+		return nil
+	case err != nil:
+		return NewStatusError(http.StatusBadRequest, err)
+	default:
+		return nil
+	}
+}
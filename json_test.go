@@ -0,0 +1,88 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+type jsonPostRequest struct {
+	ID     int64  `param:"id" json:"-"`
+	Fields string `query:"fields" json:"-"`
+	Title  string `json:"title"`
+}
+
+type jsonPostResponse struct {
+	ID     int64  `json:"id"`
+	Fields string `json:"fields,omitempty"`
+	Title  string `json:"title"`
+}
+
+func TestJSON(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	post := func(ctx context.Context, req jsonPostRequest) (*jsonPostResponse, error) {
+		if req.Title == "error" {
+			return nil, NewStatusError(http.StatusConflict, errors.New("标题已经存在"))
+		}
+		return &jsonPostResponse{ID: req.ID, Fields: req.Fields, Title: req.Title}, nil
+	}
+	del := func(ctx context.Context, req jsonPostRequest) (struct{}, error) {
+		return struct{}{}, nil
+	}
+	count := func(ctx context.Context, req int) (int, error) {
+		return req + 1, nil
+	}
+
+	srvmux.Resource("/posts/{id:\\d+}").
+		GetE(JSON(post)).
+		PostE(JSONStatus(http.StatusCreated, post)).
+		DeleteE(JSON(del))
+	srvmux.Prefix("/api").PostE("/count", JSON(count))
+
+	test := func(method, path, body, contentType string, status int, resp string) {
+		var r *http.Request
+		if body == "" {
+			r = httptest.NewRequest(method, path, nil)
+		} else {
+			r = httptest.NewRequest(method, path, strings.NewReader(body))
+		}
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+
+		w := httptest.NewRecorder()
+		srvmux.ServeHTTP(w, r)
+		a.Equal(w.Code, status, "%s %s 的状态码为 %d", method, path, w.Code)
+		if resp != "" {
+			a.Equal(w.Body.String(), resp)
+		}
+	}
+
+	test(http.MethodGet, "/posts/1?fields=title", "", "", http.StatusOK, `{"id":1,"fields":"title","title":""}`)
+	test(http.MethodPost, "/posts/1", `{"title":"abc"}`, "application/json; charset=utf-8", http.StatusCreated, `{"id":1,"title":"abc"}`)
+	test(http.MethodPost, "/posts/1", `{"title":"abc"}`, "", http.StatusCreated, `{"id":1,"title":"abc"}`)
+	test(http.MethodDelete, "/posts/1", "", "", http.StatusNoContent, "")
+	test(http.MethodPost, "/api/count", "5", "application/json", http.StatusOK, "6") // JSON 不区分请求方法
+
+	// 报文格式错误
+	test(http.MethodPost, "/posts/1", `{"title":`, "application/json", http.StatusBadRequest, "")
+	test(http.MethodPost, "/posts/1", `title=abc`, "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType, "")
+
+	// 路由参数超出范围
+	test(http.MethodGet, "/posts/99999999999999999999", "", "", http.StatusBadRequest, "")
+
+	// f 返回的错误
+	test(http.MethodPost, "/posts/1", `{"title":"error"}`, "application/json", http.StatusConflict, "")
+}
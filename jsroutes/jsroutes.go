@@ -0,0 +1,234 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package jsroutes 将命名的路由项导出给 JavaScript/TypeScript 前端使用。
+//
+// 可以导出为 JSON 格式的清单文件，也可以导出为 TypeScript 模块，
+// 模块中为每一个路由项生成一个类型化的地址构建函数：
+//  manifest, err := jsroutes.New(m)
+//  manifest.WriteJSON(jsonFile)
+//  manifest.WriteTS(tsFile)
+//
+// 带正则表达式的参数，在 TypeScript 中会在运行时进行验证，
+// 对于无法在 JavaScript 中使用的正则表达式，则仅作为文档注释输出。
+package jsroutes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/issue9/mux"
+	"github.com/issue9/mux/internal/pattern"
+)
+
+// 参数类型
+const (
+	TypeNumber = "number"
+	TypeString = "string"
+)
+
+// Go 中可用，但在 JavaScript 中无法使用或是含义不同的正则语法
+var jsIncompatible = regexp.MustCompile(`\(\?[a-zA-Z]|\\[AzpPQE]|\[\[:`)
+
+// Manifest 表示所有命名的路由项
+type Manifest struct {
+	Routes []*Route `json:"routes"`
+}
+
+// Route 表示单个命名的路由项
+type Route struct {
+	Name    string   `json:"name"`
+	Pattern string   `json:"pattern"`           // 完整的匹配模式
+	Path    string   `json:"path"`              // 去掉正则表达式之后的路径模板，比如 /posts/{id}
+	Methods []string `json:"methods,omitempty"` // 支持的请求方法，路由项不存在时为空
+	Params  []*Param `json:"params,omitempty"`
+}
+
+// Param 表示路由项中的参数
+type Param struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`             // 参数类型，可以是 number 或 string
+	Regexp string `json:"regexp,omitempty"` // 参数的正则表达式，命名参数为空
+}
+
+// New 根据 m 中所有命名的路由项生成 Manifest，路由项按名称排序。
+func New(m *mux.Mux) (*Manifest, error) {
+	methods := make(map[string][]string, 50)
+	for _, r := range m.Routes() {
+		methods[r.Pattern()] = r.Methods()
+	}
+
+	names := m.Names()
+	manifest := &Manifest{Routes: make([]*Route, 0, len(names))}
+	for name, pattern := range names {
+		r, err := newRoute(name, pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.Methods = methods[pattern]
+		manifest.Routes = append(manifest.Routes, r)
+	}
+
+	sort.Slice(manifest.Routes, func(i, j int) bool {
+		return manifest.Routes[i].Name < manifest.Routes[j].Name
+	})
+
+	return manifest, nil
+}
+
+func newRoute(name, p string) (*Route, error) {
+	path, segs, err := pattern.Path(p)
+	if err != nil {
+		return nil, fmt.Errorf("路由项 %s 出错：%s", name, err.Error())
+	}
+
+	r := &Route{Name: name, Pattern: p, Path: path}
+	for _, seg := range segs {
+		param := &Param{Name: seg.Name, Type: TypeString, Regexp: seg.Regexp}
+		if pattern.IsInt(seg.Regexp) {
+			param.Type = TypeNumber
+		}
+		r.Params = append(r.Params, param)
+	}
+
+	return r, nil
+}
+
+// WriteJSON 以 JSON 格式输出
+func (m *Manifest) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteTS 输出 TypeScript 模块。
+//
+// 模块中包含了名为 routes 的常量，保存了各个路由项的匹配模式和请求方法，
+// 以及每个路由项的地址构建函数，函数名为 url 加上驼峰形式的路由项名称，
+// 比如路由项 user-post 对应的函数为：
+//  export function urlUserPost(params: { "uid": number; "slug": string }): string
+// 与 Mux.URL 相同，参数值不会被转义。若有重名的函数，则返回错误。
+func (m *Manifest) WriteTS(w io.Writer) error {
+	funcs := new(bytes.Buffer)
+	names := make(map[string]string, len(m.Routes))
+	var useCheck bool
+
+	for _, r := range m.Routes {
+		fn := "url" + pattern.ExportName(r.Name)
+		if name, found := names[fn]; found {
+			return fmt.Errorf("路由项 %s 和 %s 生成的函数名 %s 相同", name, r.Name, fn)
+		}
+		names[fn] = r.Name
+
+		useCheck = writeFunc(funcs, fn, r) || useCheck
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by github.com/issue9/mux/jsroutes. DO NOT EDIT.")
+
+	if useCheck {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "function check(name: string, value: string, re: RegExp): string {")
+		fmt.Fprintln(buf, "    if (!re.test(value)) {")
+		fmt.Fprintln(buf, "        throw new Error(`参数 ${name} 的值 ${value} 格式不正确`);")
+		fmt.Fprintln(buf, "    }")
+		fmt.Fprintln(buf, "    return value;")
+		fmt.Fprintln(buf, "}")
+	}
+
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "export const routes = {")
+	for _, r := range m.Routes {
+		methods := make([]string, 0, len(r.Methods))
+		for _, method := range r.Methods {
+			methods = append(methods, quote(method))
+		}
+		fmt.Fprintf(buf, "    %s: { pattern: %s, methods: [%s] },\n", quote(r.Name), quote(r.Pattern), strings.Join(methods, ", "))
+	}
+	fmt.Fprintln(buf, "} as const;")
+
+	buf.Write(funcs.Bytes())
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// 输出单个路由项的地址构建函数，返回值表示是否用到了 check 函数。
+func writeFunc(w io.Writer, fn string, r *Route) bool {
+	var useCheck bool
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "/**")
+	fmt.Fprintf(w, " * 路由项 %s 的地址：%s\n", comment(r.Name), comment(r.Pattern))
+	if len(r.Params) > 0 {
+		fmt.Fprintln(w, " *")
+	}
+	for _, p := range r.Params {
+		switch {
+		case p.Regexp == "":
+			fmt.Fprintf(w, " * @param params.%s\n", comment(p.Name))
+		case jsIncompatible.MatchString(p.Regexp):
+			fmt.Fprintf(w, " * @param params.%s 需要匹配正则表达式 %s（无法在 JavaScript 中验证）\n", comment(p.Name), comment(p.Regexp))
+		default:
+			fmt.Fprintf(w, " * @param params.%s 需要匹配正则表达式 %s\n", comment(p.Name), comment(p.Regexp))
+		}
+	}
+	fmt.Fprintln(w, " */")
+
+	fields := make([]string, 0, len(r.Params))
+	for _, p := range r.Params {
+		fields = append(fields, quote(p.Name)+": "+p.Type)
+	}
+	params := ""
+	if len(fields) > 0 {
+		params = "params: { " + strings.Join(fields, "; ") + " }"
+	}
+
+	exprs := make([]string, 0, len(r.Params)*2)
+	path := r.Path
+	for _, p := range r.Params {
+		placeholder := "{" + p.Name + "}"
+		index := strings.Index(path, placeholder)
+		if index > 0 {
+			exprs = append(exprs, quote(path[:index]))
+		}
+		path = path[index+len(placeholder):]
+
+		val := "String(params[" + quote(p.Name) + "])"
+		if p.Regexp != "" && !jsIncompatible.MatchString(p.Regexp) {
+			val = "check(" + quote(p.Name) + ", " + val + ", new RegExp(" + quote("^(?:"+p.Regexp+")$") + "))"
+			useCheck = true
+		}
+		exprs = append(exprs, val)
+	}
+	if path != "" || len(exprs) == 0 {
+		exprs = append(exprs, quote(path))
+	}
+
+	fmt.Fprintf(w, "export function %s(%s): string {\n", fn, params)
+	fmt.Fprintf(w, "    return %s;\n", strings.Join(exprs, " + "))
+	fmt.Fprintln(w, "}")
+
+	return useCheck
+}
+
+// 转换成 JavaScript 的字符串
+func quote(s string) string {
+	data, _ := json.Marshal(s) // 字符串不会出错
+	return string(data)
+}
+
+// 避免在注释中出现 */
+func comment(s string) string {
+	return strings.Replace(s, "*/", "*\\/", -1)
+}
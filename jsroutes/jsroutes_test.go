@@ -0,0 +1,185 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package jsroutes
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+)
+
+func newManifest(a *assert.Assertion) *Manifest {
+	m := mux.New(false, false, nil, nil)
+	m.Get("/users/{uid:\\d+}/posts/{slug}", http.NotFoundHandler()).
+		Post("/users/{uid:\\d+}/posts/{slug}", http.NotFoundHandler())
+	a.NotError(m.Name("user-post", "/users/{uid:\\d+}/posts/{slug}"))
+	a.NotError(m.Name("home", "/"))
+	a.NotError(m.Name("file", "/files/{path}.{ext:(?i)[a-z]+}"))
+
+	manifest, err := New(m)
+	a.NotError(err).NotNil(manifest)
+	return manifest
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t)
+	manifest := newManifest(a)
+
+	a.Equal(manifest.Routes, []*Route{
+		{
+			Name:    "file",
+			Pattern: "/files/{path}.{ext:(?i)[a-z]+}",
+			Path:    "/files/{path}.{ext}",
+			Params: []*Param{
+				{Name: "path", Type: TypeString},
+				{Name: "ext", Type: TypeString, Regexp: "(?i)[a-z]+"},
+			},
+		},
+		{Name: "home", Pattern: "/", Path: "/"},
+		{
+			Name:    "user-post",
+			Pattern: "/users/{uid:\\d+}/posts/{slug}",
+			Path:    "/users/{uid}/posts/{slug}",
+			Methods: []string{http.MethodGet, http.MethodOptions, http.MethodPost},
+			Params: []*Param{
+				{Name: "uid", Type: TypeNumber, Regexp: "\\d+"},
+				{Name: "slug", Type: TypeString},
+			},
+		},
+	})
+
+	// 语法错误
+	m := mux.New(false, false, nil, nil)
+	a.NotError(m.Name("post", "/posts/{slug"))
+	manifest, err := New(m)
+	a.Error(err).Nil(manifest)
+}
+
+func TestManifest_WriteJSON(t *testing.T) {
+	a := assert.New(t)
+	manifest := newManifest(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(manifest.WriteJSON(buf))
+	a.Equal(buf.String(), `{
+  "routes": [
+    {
+      "name": "file",
+      "pattern": "/files/{path}.{ext:(?i)[a-z]+}",
+      "path": "/files/{path}.{ext}",
+      "params": [
+        {
+          "name": "path",
+          "type": "string"
+        },
+        {
+          "name": "ext",
+          "type": "string",
+          "regexp": "(?i)[a-z]+"
+        }
+      ]
+    },
+    {
+      "name": "home",
+      "pattern": "/",
+      "path": "/"
+    },
+    {
+      "name": "user-post",
+      "pattern": "/users/{uid:\\d+}/posts/{slug}",
+      "path": "/users/{uid}/posts/{slug}",
+      "methods": [
+        "GET",
+        "OPTIONS",
+        "POST"
+      ],
+      "params": [
+        {
+          "name": "uid",
+          "type": "number",
+          "regexp": "\\d+"
+        },
+        {
+          "name": "slug",
+          "type": "string"
+        }
+      ]
+    }
+  ]
+}
+`)
+}
+
+func TestManifest_WriteTS(t *testing.T) {
+	a := assert.New(t)
+	manifest := newManifest(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(manifest.WriteTS(buf))
+	a.Equal(buf.String(), `// Code generated by github.com/issue9/mux/jsroutes. DO NOT EDIT.
+
+function check(name: string, value: string, re: RegExp): string {
+    if (!re.test(value)) {
+        throw new Error(`+"`参数 ${name} 的值 ${value} 格式不正确`"+`);
+    }
+    return value;
+}
+
+export const routes = {
+    "file": { pattern: "/files/{path}.{ext:(?i)[a-z]+}", methods: [] },
+    "home": { pattern: "/", methods: [] },
+    "user-post": { pattern: "/users/{uid:\\d+}/posts/{slug}", methods: ["GET", "OPTIONS", "POST"] },
+} as const;
+
+/**
+ * 路由项 file 的地址：/files/{path}.{ext:(?i)[a-z]+}
+ *
+ * @param params.path
+ * @param params.ext 需要匹配正则表达式 (?i)[a-z]+（无法在 JavaScript 中验证）
+ */
+export function urlFile(params: { "path": string; "ext": string }): string {
+    return "/files/" + String(params["path"]) + "." + String(params["ext"]);
+}
+
+/**
+ * 路由项 home 的地址：/
+ */
+export function urlHome(): string {
+    return "/";
+}
+
+/**
+ * 路由项 user-post 的地址：/users/{uid:\d+}/posts/{slug}
+ *
+ * @param params.uid 需要匹配正则表达式 \d+
+ * @param params.slug
+ */
+export function urlUserPost(params: { "uid": number; "slug": string }): string {
+    return "/users/" + check("uid", String(params["uid"]), new RegExp("^(?:\\d+)$")) + "/posts/" + String(params["slug"]);
+}
+`)
+
+	// 不需要 check
+	buf.Reset()
+	manifest = &Manifest{Routes: []*Route{{Name: "home", Pattern: "/", Path: "/"}}}
+	a.NotError(manifest.WriteTS(buf))
+	a.False(bytes.Contains(buf.Bytes(), []byte("function check")))
+
+	// 函数名相同
+	manifest = &Manifest{Routes: []*Route{
+		{Name: "user-post", Pattern: "/users/posts", Path: "/users/posts"},
+		{Name: "user.post", Pattern: "/users/posts", Path: "/users/posts"},
+	}}
+	a.Error(manifest.WriteTS(buf))
+}
+
+func TestComment(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(comment("/posts/{id:a*/b}"), "/posts/{id:a*\\/b}")
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+var (
+	defaultServiceUnavailable    = ProblemHandler(http.StatusServiceUnavailable)
+	defaultRequestEntityTooLarge = ProblemHandler(http.StatusRequestEntityTooLarge)
+)
+
+// SetTimeout 指定 pattern 的处理时限，为 0 时表示使用 Prefix.SetTimeout 中的设置。
+//
+// 处理时限仅通过 http.Request.Context() 传递给处理函数，需要处理函数自行根据 Context 结束操作。
+// 与 http.TimeoutHandler 不同，不会中断处理函数的执行，也不会修改处理函数输出的内容，
+// 即使处理函数在超时之后才返回。
+// 通过 HandleE 等方法添加的处理函数，若返回 context.DeadlineExceeded，
+// 则调用 Mux.SetServiceUnavailable 指定的函数。
+//
+// 只能为已经存在的路由项设置，否则返回 ErrRouteNotExists；
+// 路由项的所有请求方法都被删除之后，此设置也将一并失效。
+func (mux *Mux) SetTimeout(pattern string, timeout time.Duration) error {
+	hs, err := mux.find(pattern)
+	if err != nil {
+		return err
+	}
+
+	hs.SetTimeout(timeout)
+	return nil
+}
+
+// SetMaxBodySize 指定 pattern 的请求报文的最大值，为 0 时表示使用 Prefix.SetMaxBodySize 中的设置。
+//
+// Content-Length 超过该值的请求，会直接调用 Mux.SetRequestEntityTooLarge 指定的函数；
+// 其它请求的报文通过 http.MaxBytesReader 限制大小，读取超出部分时返回 *http.MaxBytesError。
+//
+// 其它说明可参考 Mux.SetTimeout。
+func (mux *Mux) SetMaxBodySize(pattern string, size int64) error {
+	hs, err := mux.find(pattern)
+	if err != nil {
+		return err
+	}
+
+	hs.SetMaxBodySize(size)
+	return nil
+}
+
+// SetServiceUnavailable 指定处理超时时的处理方式，为 nil 时会恢复成默认的处理方式。
+//
+// 仅在 HandlerFuncE 因超时而返回 context.DeadlineExceeded 时调用，具体可参考 Mux.SetTimeout。
+// 默认的处理方式由 ProblemHandler 生成，输出 503。
+func (mux *Mux) SetServiceUnavailable(h http.HandlerFunc) *Mux {
+	if h == nil {
+		h = defaultServiceUnavailable
+	}
+	mux.serviceUnavailable = h
+
+	return mux
+}
+
+// SetRequestEntityTooLarge 指定请求报文超过大小限制时的处理方式，为 nil 时会恢复成默认的处理方式。
+//
+// 默认的处理方式由 ProblemHandler 生成，输出 413。
+func (mux *Mux) SetRequestEntityTooLarge(h http.HandlerFunc) *Mux {
+	if h == nil {
+		h = defaultRequestEntityTooLarge
+	}
+	mux.requestEntityTooLarge = h
+
+	return mux
+}
+
+// 获取 path 对应的处理时限和报文大小限制，hs 中的设置优先于 Prefix 中的设置。
+func (mux *Mux) limits(path string, hs *handlers.Handlers) (timeout time.Duration, size int64) {
+	timeout, size = hs.Timeout(), hs.MaxBodySize()
+	if timeout > 0 && size > 0 {
+		return timeout, size
+	}
+
+	mux.prefixesMu.RLock()
+	defer mux.prefixesMu.RUnlock()
+
+	for _, ph := range mux.prefixes {
+		if !ph.prefix.Match(path) {
+			continue
+		}
+
+		if timeout <= 0 {
+			timeout = ph.timeout
+		}
+		if size <= 0 {
+			size = ph.maxBodySize
+		}
+	}
+
+	return timeout, size
+}
+
+// 在 path 对应的处理时限和报文大小的限制下执行 h。
+func (mux *Mux) serveLimited(w http.ResponseWriter, r *http.Request, path string, hs *handlers.Handlers, h http.Handler) {
+	timeout, size := mux.limits(path, hs)
+
+	if size > 0 {
+		if r.ContentLength > size {
+			mux.requestEntityTooLarge(w, r)
+			return
+		}
+
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, size)
+		}
+	}
+
+	if timeout <= 0 {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	h.ServeHTTP(w, r.WithContext(ctx))
+}
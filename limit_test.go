@@ -0,0 +1,148 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestMux_SetMaxBodySize(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	read := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+	readE := func(w http.ResponseWriter, r *http.Request) error {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return nil
+	}
+
+	api := srvmux.Prefix("/api").SetMaxBodySize(5)
+	api.PostFunc("/posts", read).
+		PostE("/posts/e", readE)
+	upload := api.Resource("/upload")
+	a.Panic(func() { upload.SetMaxBodySize(10) }) // 路由项不存在
+	upload.PostFunc(read).SetMaxBodySize(10)
+	a.True(errors.Is(srvmux.SetMaxBodySize("/files", 3), ErrRouteNotExists))
+	a.Equal(len(srvmux.Routes()), 3) // 不会生成新的路由项
+	srvmux.PostFunc("/files", read).
+		PostFunc("/unlimited", read).
+		PostFunc("/apiv2/posts", read)
+	a.NotError(srvmux.SetMaxBodySize("/files", 3))
+	a.Error(srvmux.SetMaxBodySize("/files/{id", 3))
+
+	test := func(path, body string, contentLength int64, status int) {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.ContentLength = contentLength
+		w := httptest.NewRecorder()
+		srvmux.ServeHTTP(w, r)
+		a.Equal(w.Code, status, "%s 的状态码为 %d", path, w.Code)
+	}
+
+	test("/api/posts", "12345", 5, http.StatusAccepted)
+	test("/api/posts", "123456", 6, http.StatusRequestEntityTooLarge)
+	test("/api/posts", "123456", -1, http.StatusBadRequest) // 未知长度，由处理函数决定
+	test("/api/posts/e", "123456", -1, http.StatusRequestEntityTooLarge)
+	test("/api/upload", "123456", 6, http.StatusAccepted) // 路由项的设置优先
+	test("/files", "1234", 4, http.StatusRequestEntityTooLarge)
+	test("/unlimited", strings.Repeat("1", 100), 100, http.StatusAccepted)
+	test("/apiv2/posts", "123456", 6, http.StatusAccepted) // 不受 /api 的限制
+
+	srvmux.SetRequestEntityTooLarge(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	test("/files", "1234", 4, http.StatusTeapot)
+	test("/api/posts/e", "123456", -1, http.StatusTeapot)
+
+	srvmux.SetRequestEntityTooLarge(nil)
+	test("/files", "1234", 4, http.StatusRequestEntityTooLarge)
+}
+
+func TestMux_SetTimeout(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	wait := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+	waitWrite := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusAccepted)
+	}
+	waitE := func(w http.ResponseWriter, r *http.Request) error {
+		<-r.Context().Done()
+		return r.Context().Err()
+	}
+	slow := func(w http.ResponseWriter, r *http.Request) { // 不理会 Context
+		time.Sleep(20 * time.Millisecond)
+	}
+	flush := func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+	deadline := func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		io.WriteString(w, "no deadline")
+	}
+
+	api := srvmux.Prefix("/api").SetTimeout(10 * time.Millisecond)
+	api.GetFunc("/wait", wait).
+		GetFunc("/write", waitWrite).
+		GetE("/e", waitE).
+		GetFunc("/slow", slow).
+		GetFunc("/flush", flush).
+		GetFunc("/deadline/{id}", deadline)
+	a.True(errors.Is(srvmux.SetTimeout("/wait", 10*time.Millisecond), ErrRouteNotExists))
+	srvmux.GetFunc("/wait", wait).
+		GetFunc("/deadline", deadline)
+	a.NotError(srvmux.SetTimeout("/wait", 10*time.Millisecond))
+
+	test := func(path string, status int) {
+		w := httptest.NewRecorder()
+		srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		a.Equal(w.Code, status, "%s 的状态码为 %d", path, w.Code)
+	}
+
+	// 正常返回的处理函数，即使已经超时，也不会修改其输出
+	test("/api/wait", http.StatusOK)
+	test("/api/write", http.StatusAccepted)
+	test("/api/slow", http.StatusOK)
+	test("/api/flush", http.StatusOK)
+	test("/wait", http.StatusOK)
+
+	test("/api/e", http.StatusServiceUnavailable)
+	test("/api/deadline/1", http.StatusAccepted)
+	test("/deadline", http.StatusOK)
+
+	srvmux.SetServiceUnavailable(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+	})
+	test("/api/e", http.StatusGatewayTimeout)
+
+	srvmux.SetServiceUnavailable(nil)
+	test("/api/e", http.StatusServiceUnavailable)
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"net/http"
+
+	"github.com/issue9/mux/internal/handlers"
+	"github.com/issue9/mux/params"
+)
+
+// Match 表示请求在 Mux 中的匹配结果，可通过 Mux.Match 获取。
+type Match struct {
+	// 匹配结果，与 Mux.ServeHTTP 的状态码相对应：
+	// 200 表示找到了对应的处理函数；
+	// 404 表示没有匹配的路由项；
+	// 405 表示路由项存在，但不支持该请求方法；
+	// 501 表示该请求方法不被支持。
+	Status int
+
+	Route   *Route        // 匹配的路由项，仅在 404 时为 nil
+	Params  params.Params // 路由参数，没有参数时为 nil
+	Handler http.Handler  // 对应的处理函数，仅在 200 时有值
+}
+
+// Match 获取请求 method 和 path 的匹配结果，但不会调用任何处理函数。
+//
+// 与 ServeHTTP 一样，会根据 New() 中的 skipCleanPath 参数决定是否处理 path。
+// 适用于需要在分发请求之前知道路由结果的场景，比如反向代理、权限网关等。
+func (mux *Mux) Match(method, path string) *Match {
+	if !mux.skipCleanPath {
+		path = cleanPath(path)
+	}
+
+	hs, ps := mux.tree.Route(path)
+	if hs == nil {
+		return &Match{Status: http.StatusNotFound}
+	}
+
+	m := &Match{Route: hs.Route()}
+	m.Params = routeParams(m.Route, ps)
+
+	switch m.Handler = hs.Handler(method); {
+	case m.Handler != nil:
+		m.Status = http.StatusOK
+	case handlers.IsSupported(method):
+		m.Status = http.StatusMethodNotAllowed
+	default:
+		m.Status = http.StatusNotImplemented
+	}
+
+	return m
+}
+
+// 从 ps 中过滤出 route 中声明的参数，与 Mux.ServeHTTP 写入请求的参数保持一致。
+//
+// 匹配过程中回溯的节点可能会在 ps 中留下其它参数。没有参数时返回 nil。
+func routeParams(route *Route, ps params.Params) params.Params {
+	var ret params.Params
+	for _, name := range route.Params() {
+		if val, found := ps[name]; found {
+			if ret == nil {
+				ret = make(params.Params, len(route.Params()))
+			}
+			ret[name] = val
+		}
+	}
+	return ret
+}
+
+// MatchRequest 获取请求 r 的匹配结果，相当于 Mux.Match(r.Method, r.URL.Path)。
+func (mux *Mux) MatchRequest(r *http.Request) *Match {
+	return mux.Match(r.Method, r.URL.Path)
+}
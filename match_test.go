@@ -0,0 +1,79 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux/params"
+)
+
+func TestMux_Match(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	served := false
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	})
+	srvmux.Get("/posts/{id:\\d+}", h).
+		Get("/posts", h)
+	a.NotError(srvmux.Name("post", "/posts/{id:\\d+}"))
+
+	m := srvmux.Match(http.MethodGet, "//posts/1")
+	a.Equal(m.Status, http.StatusOK).
+		NotNil(m.Handler).
+		Equal(m.Route.Name(), "post").
+		Equal(m.Params, params.Params{"id": "1"})
+
+	m = srvmux.Match(http.MethodGet, "/posts")
+	a.Equal(m.Status, http.StatusOK).
+		NotNil(m.Handler).
+		Equal(m.Route.Pattern(), "/posts").
+		Nil(m.Params)
+
+	m = srvmux.Match(http.MethodPost, "/posts/1")
+	a.Equal(m.Status, http.StatusMethodNotAllowed).
+		Nil(m.Handler).
+		Equal(m.Route.Allow(), "GET, OPTIONS").
+		Equal(m.Params, params.Params{"id": "1"})
+
+	m = srvmux.Match("FOO", "/posts/1")
+	a.Equal(m.Status, http.StatusNotImplemented).
+		Nil(m.Handler).
+		NotNil(m.Route)
+
+	m = srvmux.Match(http.MethodGet, "/posts/abc")
+	a.Equal(m.Status, http.StatusNotFound).
+		Nil(m.Handler).
+		Nil(m.Route).
+		Nil(m.Params)
+
+	r := httptest.NewRequest(http.MethodGet, "/posts/5", nil)
+	m = srvmux.MatchRequest(r)
+	a.Equal(m.Status, http.StatusOK).
+		Equal(m.Params, params.Params{"id": "5"})
+
+	a.False(served) // 不会调用处理函数
+
+	// 仅包含路由项中声明的参数，与 ServeHTTP 保持一致
+	srvmux.Get("/users/{id:\\d+}/{action}/edit", h).
+		Get("/users/{name}/x/view", h)
+	m = srvmux.Match(http.MethodGet, "/users/1/x/view")
+	a.Equal(m.Status, http.StatusOK).
+		Equal(m.Route.Pattern(), "/users/{name}/x/view").
+		Equal(m.Params, params.Params{"name": "1"})
+
+	// skipCleanPath
+	srvmux = New(false, true, nil, nil)
+	a.NotNil(srvmux)
+	srvmux.Get("/posts/{id:\\d+}", h)
+	a.Equal(srvmux.Match(http.MethodGet, "//posts/1").Status, http.StatusNotFound)
+	a.Equal(srvmux.Match(http.MethodGet, "/posts/1").Status, http.StatusOK)
+}
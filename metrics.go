@@ -0,0 +1,270 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+// OtherMethod 不支持的请求方法在统计信息中统一以此值表示，避免无限增长的标签值。
+const OtherMethod = "OTHER"
+
+// DefaultBuckets 默认的请求耗时的分组，单位为秒，与 Prometheus 客户端的默认值相同。
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics 以路由项和请求方法为单位统计请求信息，
+// 并以 Prometheus 的文本格式输出，可通过 Mux.SetMetrics 指定给 Mux：
+//  metrics := mux.NewMetrics()
+//  m.SetMetrics(metrics)
+//  m.Get("/metrics", metrics)
+//
+// 输出以下统计项：
+//  mux_requests_total                请求数量，包含 route、method 和 status 标签；
+//  mux_request_duration_seconds      请求耗时的直方图，包含 route 和 method 标签。
+// route 为路由项的匹配模式而不是实际的请求路径，没有匹配的路由项时为空，
+// 所以 404 会统一计入 route 为空的统计项中。
+type Metrics struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[metricKey]*metricSeries
+}
+
+type metricKey struct {
+	route  string
+	method string
+}
+
+type metricSeries struct {
+	statuses map[int]uint64
+	buckets  []uint64 // 与 Metrics.buckets 对应，表示落在该分组中的请求数量，非累加值
+	sum      float64
+	count    uint64
+}
+
+// NewMetrics 声明一个新的 Metrics 实例。
+//
+// buckets 为请求耗时的分组的上限，单位为秒，为空时采用 DefaultBuckets。
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+
+	return &Metrics{
+		buckets: b,
+		series:  make(map[metricKey]*metricSeries, 50),
+	}
+}
+
+// SetMetrics 指定统计请求信息的 Metrics 实例，为 nil 时表示不统计，这也是默认值。
+//
+// 未通过 SetRecovery 捕获的 panic，若尚未输出报头，其请求以 500 计入统计。
+// 未指定 SetRecovery 时无法获知 panic 的值，所以在输出报头之前以 http.ErrAbortHandler
+// 中断的请求也会以 500 计入；指定了 SetRecovery 时则不作统计。
+func (mux *Mux) SetMetrics(m *Metrics) *Mux {
+	mux.metrics = m
+	return mux
+}
+
+// 将请求的统计信息写入 Mux.metrics，hs 为与请求匹配的路由项。
+//
+// 只能通过 defer 调用。为了保留 panic 的调用栈，不会调用 recover，
+// 而是通过 statusWriter.completed 判断处理过程是否被 panic 中断：
+// 被中断且尚未输出报头的以 500 计入统计，已经输出报头的以输出的状态码计入；
+// 由 Mux.recover 判定为 http.ErrAbortHandler 的，不会以 500 计入。
+func (mux *Mux) observe(w *statusWriter, method string, hs *handlers.Handlers, start time.Time) {
+	status := w.status
+	switch {
+	case status != 0:
+	case w.completed:
+		status = http.StatusOK
+	case w.aborted: // 客户端中断的请求，没有输出任何内容，不作统计。
+		return
+	default:
+		status = http.StatusInternalServerError
+	}
+
+	var route string
+	if hs != nil {
+		route = hs.Route().Pattern()
+	}
+
+	mux.metrics.observe(route, method, status, time.Since(start))
+}
+
+// 记录一次请求，route 为路由项的匹配模式。
+func (m *Metrics) observe(route, method string, status int, duration time.Duration) {
+	if !handlers.IsSupported(method) {
+		method = OtherMethod
+	}
+	key := metricKey{route: route, method: method}
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, found := m.series[key]
+	if !found {
+		s = &metricSeries{
+			statuses: make(map[int]uint64, 4),
+			buckets:  make([]uint64, len(m.buckets)),
+		}
+		m.series[key] = s
+	}
+
+	s.statuses[status]++
+	s.sum += seconds
+	s.count++
+	if index := sort.SearchFloat64s(m.buckets, seconds); index < len(m.buckets) {
+		s.buckets[index]++
+	}
+}
+
+// ServeHTTP 以 Prometheus 的文本格式输出统计信息
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(m.bytes())
+}
+
+func (m *Metrics) bytes() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]metricKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	buf := new(bytes.Buffer)
+
+	buf.WriteString("# HELP mux_requests_total 请求数量\n")
+	buf.WriteString("# TYPE mux_requests_total counter\n")
+	for _, key := range keys {
+		s := m.series[key]
+		statuses := make([]int, 0, len(s.statuses))
+		for status := range s.statuses {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+
+		for _, status := range statuses {
+			buf.WriteString("mux_requests_total{")
+			writeLabels(buf, key)
+			buf.WriteString(`,status="` + strconv.Itoa(status) + `"} `)
+			buf.WriteString(strconv.FormatUint(s.statuses[status], 10))
+			buf.WriteByte('\n')
+		}
+	}
+
+	buf.WriteString("# HELP mux_request_duration_seconds 请求耗时\n")
+	buf.WriteString("# TYPE mux_request_duration_seconds histogram\n")
+	for _, key := range keys {
+		s := m.series[key]
+
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += s.buckets[i]
+			writeBucket(buf, key, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		writeBucket(buf, key, "+Inf", s.count)
+
+		buf.WriteString("mux_request_duration_seconds_sum{")
+		writeLabels(buf, key)
+		buf.WriteString("} " + strconv.FormatFloat(s.sum, 'g', -1, 64) + "\n")
+
+		buf.WriteString("mux_request_duration_seconds_count{")
+		writeLabels(buf, key)
+		buf.WriteString("} " + strconv.FormatUint(s.count, 10) + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+func writeBucket(buf *bytes.Buffer, key metricKey, le string, count uint64) {
+	buf.WriteString("mux_request_duration_seconds_bucket{")
+	writeLabels(buf, key)
+	buf.WriteString(`,le="` + le + `"} `)
+	buf.WriteString(strconv.FormatUint(count, 10))
+	buf.WriteByte('\n')
+}
+
+func writeLabels(buf *bytes.Buffer, key metricKey) {
+	buf.WriteString(`route="` + escapeLabel(key.route) + `",method="` + escapeLabel(key.method) + `"`)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// 转义标签值中的 \、" 和换行符
+func escapeLabel(v string) string {
+	return labelReplacer.Replace(v)
+}
+
+// 记录状态码的 http.ResponseWriter。
+//
+// http.Flusher 和 http.Hijacker 会转发给原始的 http.ResponseWriter，
+// 原始对象不支持时，Flush 不作任何操作，Hijack 返回 http.ErrNotSupported。
+// Hijack 之后的请求以 101 计入统计。
+type statusWriter struct {
+	http.ResponseWriter
+	status    int  // 已经输出的状态码，为 0 表示尚未输出报头
+	completed bool // 处理过程是否正常结束，未被 panic 中断
+	aborted   bool // 是否被 http.ErrAbortHandler 中断，仅在指定了 Mux.recovery 时才能判断
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 && status >= http.StatusOK { // 忽略 1XX 的状态码
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush 实现 http.Flusher 接口
+func (w *statusWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 实现 http.Hijacker 接口
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap 返回原始的 http.ResponseWriter，方便 http.ResponseController 使用。
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestNewMetrics(t *testing.T) {
+	a := assert.New(t)
+
+	m := NewMetrics()
+	a.Equal(m.buckets, DefaultBuckets)
+
+	m = NewMetrics(1, 0.5)
+	a.Equal(m.buckets, []float64{0.5, 1})
+}
+
+func TestMetrics_observe(t *testing.T) {
+	a := assert.New(t)
+	m := NewMetrics(0.5, 1)
+
+	m.observe("/posts/{id:\\d+}", http.MethodGet, http.StatusOK, 100*time.Millisecond)
+	m.observe("/posts/{id:\\d+}", http.MethodGet, http.StatusOK, 700*time.Millisecond)
+	m.observe("/posts/{id:\\d+}", http.MethodGet, http.StatusNotFound, 2*time.Second)
+	m.observe("", "LOCK", http.StatusNotFound, 100*time.Millisecond)
+
+	a.Equal(string(m.bytes()), `# HELP mux_requests_total 请求数量
+# TYPE mux_requests_total counter
+mux_requests_total{route="",method="OTHER",status="404"} 1
+mux_requests_total{route="/posts/{id:\\d+}",method="GET",status="200"} 2
+mux_requests_total{route="/posts/{id:\\d+}",method="GET",status="404"} 1
+# HELP mux_request_duration_seconds 请求耗时
+# TYPE mux_request_duration_seconds histogram
+mux_request_duration_seconds_bucket{route="",method="OTHER",le="0.5"} 1
+mux_request_duration_seconds_bucket{route="",method="OTHER",le="1"} 1
+mux_request_duration_seconds_bucket{route="",method="OTHER",le="+Inf"} 1
+mux_request_duration_seconds_sum{route="",method="OTHER"} 0.1
+mux_request_duration_seconds_count{route="",method="OTHER"} 1
+mux_request_duration_seconds_bucket{route="/posts/{id:\\d+}",method="GET",le="0.5"} 1
+mux_request_duration_seconds_bucket{route="/posts/{id:\\d+}",method="GET",le="1"} 2
+mux_request_duration_seconds_bucket{route="/posts/{id:\\d+}",method="GET",le="+Inf"} 3
+mux_request_duration_seconds_sum{route="/posts/{id:\\d+}",method="GET"} 2.8
+mux_request_duration_seconds_count{route="/posts/{id:\\d+}",method="GET"} 3
+`)
+}
+
+func TestMux_SetMetrics(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	metrics := NewMetrics()
+	srvmux.SetMetrics(metrics).
+		Get("/metrics", metrics).
+		Get("/posts/{id:\\d+}", buildHandler(http.StatusAccepted)).
+		GetFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+			panic("panic")
+		})
+	srvmux.SetRecovery(func(w http.ResponseWriter, r *http.Request, p *Panic) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	serve := func(method, path string) {
+		srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	}
+	serve(http.MethodGet, "/posts/1")
+	serve(http.MethodGet, "/posts/2")
+	serve(http.MethodPost, "/posts/1")
+	serve(http.MethodGet, "/not-exists")
+	serve(http.MethodGet, "/panic")
+
+	w := httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	a.Equal(w.Code, http.StatusOK)
+	a.True(strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+
+	body := w.Body.String()
+	for _, line := range []string{
+		`mux_requests_total{route="/posts/{id:\\d+}",method="GET",status="202"} 2`,
+		`mux_requests_total{route="/posts/{id:\\d+}",method="POST",status="405"} 1`,
+		`mux_requests_total{route="",method="GET",status="404"} 1`,
+		`mux_requests_total{route="/panic",method="GET",status="500"} 1`,
+		`mux_request_duration_seconds_count{route="/posts/{id:\\d+}",method="GET"} 2`,
+		`mux_request_duration_seconds_bucket{route="/posts/{id:\\d+}",method="GET",le="+Inf"} 2`,
+	} {
+		a.True(strings.Contains(body, line), "不包含 %s", line)
+	}
+
+	// 不再统计
+	srvmux.SetMetrics(nil)
+	serve(http.MethodGet, "/posts/1")
+	a.True(strings.Contains(string(metrics.bytes()), `mux_requests_total{route="/posts/{id:\\d+}",method="GET",status="202"} 2`))
+}
+
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	panic("panic")
+}
+
+func TestMux_SetMetrics_panic(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	metrics := NewMetrics()
+	srvmux.SetMetrics(metrics).
+		GetFunc("/panic", panicHandler).
+		GetFunc("/written", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			panic("panic")
+		}).
+		GetFunc("/abort", func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+
+	// 未指定 SetRecovery，panic 会继续向上传递，且保留原始的调用栈
+	serve := func(path string) (v interface{}, stack string) {
+		defer func() {
+			v = recover()
+			stack = string(debug.Stack())
+		}()
+		srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		return nil, ""
+	}
+	v, stack := serve("/panic")
+	a.Equal(v, "panic").True(strings.Contains(stack, "panicHandler"))
+	v, _ = serve("/written")
+	a.Equal(v, "panic")
+
+	body := string(metrics.bytes())
+	a.True(strings.Contains(body, `mux_requests_total{route="/panic",method="GET",status="500"} 1`))
+	a.True(strings.Contains(body, `mux_requests_total{route="/written",method="GET",status="202"} 1`)) // 已经输出报头
+
+	// 指定了 SetRecovery，http.ErrAbortHandler 不计入统计
+	srvmux.SetRecovery(DefaultRecoveryHandler)
+	v, _ = serve("/abort")
+	a.Equal(v, http.ErrAbortHandler)
+	a.False(strings.Contains(string(metrics.bytes()), `route="/abort"`))
+}
+
+func TestMux_SetMetrics_flush_hijack(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	metrics := NewMetrics()
+	srvmux.SetMetrics(metrics).
+		GetFunc("/flush", func(w http.ResponseWriter, r *http.Request) {
+			w.(http.Flusher).Flush()
+		}).
+		GetFunc("/hijack", func(w http.ResponseWriter, r *http.Request) {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			defer conn.Close()
+			buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+			buf.Flush()
+		})
+
+	w := httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/flush", nil))
+	a.True(w.Flushed)
+
+	// httptest.ResponseRecorder 不支持 Hijack
+	w = httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hijack", nil))
+	a.Equal(w.Code, http.StatusInternalServerError)
+
+	srv := httptest.NewServer(srvmux)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/hijack")
+	a.NotError(err).NotNil(resp)
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	a.NotError(err).Equal(string(data), "ok")
+
+	// 客户端读取完内容时，服务端可能还未完成统计
+	hijacked := `mux_requests_total{route="/hijack",method="GET",status="101"} 1`
+	for i := 0; i < 100 && !strings.Contains(string(metrics.bytes()), hijacked); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	body := string(metrics.bytes())
+	a.True(strings.Contains(body, `mux_requests_total{route="/flush",method="GET",status="200"} 1`))
+	a.True(strings.Contains(body, hijacked))
+	a.True(strings.Contains(body, `mux_requests_total{route="/hijack",method="GET",status="500"} 1`))
+}
+
+func TestEscapeLabel(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(escapeLabel(`/posts/{id:\d+}`), `/posts/{id:\\d+}`)
+	a.Equal(escapeLabel("a\"b\nc"), `a\"b\nc`)
+}
@@ -5,28 +5,32 @@
 package mux
 
 import (
-	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/issue9/mux/dialect"
+	"github.com/issue9/mux/internal/handlers"
 	"github.com/issue9/mux/internal/tree"
 	"github.com/issue9/mux/params"
 )
 
 var (
-	defaultNotFound = func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-	}
-
-	defaultMethodNotAllowed = func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-	}
+	defaultNotFound         = ProblemHandler(http.StatusNotFound)
+	defaultMethodNotAllowed = ProblemHandler(http.StatusMethodNotAllowed)
+	defaultNotImplemented   = ProblemHandler(http.StatusNotImplemented)
 )
 
-// ErrNameExists 当为一个路由项命名时，若存在相同名称的，则返回此错误信息。
-var ErrNameExists = errors.New("存在相同名称的路由项")
+var (
+	// ErrNameExists 当为一个路由项命名时，若存在相同名称的，则返回此错误信息。
+	ErrNameExists = errors.New("存在相同名称的路由项")
+
+	// ErrRouteNotExists 当为不存在的路由项指定设置时，返回此错误信息。
+	ErrRouteNotExists = errors.New("路由项不存在")
+)
 
 // Mux 提供了强大的路由匹配功能，可以对路径按正则或是请求方法进行匹配。
 //
@@ -41,12 +45,29 @@ type Mux struct {
 	skipCleanPath    bool
 	notFound         http.HandlerFunc
 	methodNotAllowed http.HandlerFunc
+	notImplemented   http.HandlerFunc
+	errorHandler     ErrorHandler
+	recovery         RecoveryHandler
+	metrics          *Metrics
+
+	serviceUnavailable    http.HandlerFunc
+	requestEntityTooLarge http.HandlerFunc
 
 	// names 保存着路由项与其名称的对应关系，默认情况下，
 	// 路由项不存在名称，但可以通过 Mux.Name() 为其指定一个名称，
 	// 之后即可以在 Mux.URL() 使用名称来查找路由项。
 	names   map[string]string
 	namesMu sync.RWMutex
+
+	// prefixes 保存由 Prefix 指定的错误处理函数，按前缀长度倒序排列，
+	// 方便 ServeHTTP 查找最深层的前缀。
+	prefixes   []*prefixHandlers
+	prefixesMu sync.RWMutex
+
+	// 路由项发生变化时的回调函数
+	addedHooks   []RouteHook
+	removedHooks []RouteHook
+	hooksMu      sync.RWMutex
 }
 
 // New 声明一个新的 Mux。
@@ -56,6 +77,9 @@ type Mux struct {
 // notFound 404 页面的处理方式，为 nil 时会调用默认的方式进行处理；
 // methodNotAllowed 405 页面的处理方式，为 nil 时会调用默认的方式进行处理，
 // 调用此方法前，会设置 Allow 报头，如果不需要，则要在 methodNotAllowed 中去掉。
+//
+// 默认的处理方式由 ProblemHandler 生成，会根据 Accept 报头输出
+// application/problem+json 或是纯文本格式的内容。
 func New(disableOptions, skipCleanPath bool, notFound, methodNotAllowed http.HandlerFunc) *Mux {
 	if notFound == nil {
 		notFound = defaultNotFound
@@ -70,21 +94,54 @@ func New(disableOptions, skipCleanPath bool, notFound, methodNotAllowed http.Han
 		skipCleanPath:    skipCleanPath,
 		notFound:         notFound,
 		methodNotAllowed: methodNotAllowed,
+		notImplemented:   defaultNotImplemented,
+		errorHandler:     DefaultErrorHandler,
+
+		serviceUnavailable:    defaultServiceUnavailable,
+		requestEntityTooLarge: defaultRequestEntityTooLarge,
 	}
 }
 
+// SetNotImplemented 指定 501 页面的处理方式，为 nil 时会恢复成默认的处理方式。
+//
+// 当请求方法不在 SupportedMethods() 之列时，会调用此函数，
+// 而不是 New() 中指定的 methodNotAllowed。
+func (mux *Mux) SetNotImplemented(h http.HandlerFunc) *Mux {
+	if h == nil {
+		h = defaultNotImplemented
+	}
+	mux.notImplemented = h
+
+	return mux
+}
+
 // Clean 清除所有的路由项
 func (mux *Mux) Clean() *Mux {
-	mux.tree.Clean("")
+	mux.clean("")
 	return mux
 }
 
+func (mux *Mux) clean(prefix string) {
+	before := mux.routeMethods()
+	mux.tree.Clean(prefix)
+	if before != nil {
+		mux.fireRemovedDiff(before, mux.routeMethods())
+	}
+}
+
 // Remove 移除指定的路由项。
 //
 // 当未指定 methods 时，将删除所有 method 匹配的项。
 // 指定错误的 methods 值，将自动忽略该值。
 func (mux *Mux) Remove(pattern string, methods ...string) *Mux {
+	hs := mux.tree.Find(pattern)
+	if hs == nil {
+		return mux
+	}
+
+	before := hs.Route().Methods()
 	mux.tree.Remove(pattern, methods...)
+	mux.fire(&mux.removedHooks, pattern, diffMethods(before, hs.Route().Methods()))
 	return mux
 }
 
@@ -93,7 +150,45 @@ func (mux *Mux) Remove(pattern string, methods ...string) *Mux {
 // pattern 为路由匹配模式，可以是正则匹配也可以是字符串匹配；
 // methods 该路由项对应的请求方法，可通过 SupportedMethods() 获得当前支持的请求方法。
 func (mux *Mux) Handle(pattern string, h http.Handler, methods ...string) error {
-	return mux.tree.Add(pattern, h, methods...)
+	// 需要在 tree.Handlers 生成节点之前获取，
+	// 新的路由项为 nil，自动生成的 OPTIONS 也会作为新添加的请求方法。
+	before := mux.methods(pattern)
+
+	hs, err := mux.tree.Handlers(pattern)
+	if err != nil {
+		return err
+	}
+
+	if err = hs.Add(h, methods...); err != nil {
+		return err
+	}
+	mux.fire(&mux.addedHooks, pattern, diffMethods(hs.Route().Methods(), before))
+
+	if hs.Route().Name() == "" {
+		mux.namesMu.RLock()
+		for name, p := range mux.names {
+			if p == pattern {
+				hs.SetName(name)
+				break
+			}
+		}
+		mux.namesMu.RUnlock()
+	}
+
+	return nil
+}
+
+// 获取 pattern 对应的路由项，不存在时返回 ErrRouteNotExists。
+//
+// 与 tree.Handlers 不同，不会生成新的节点，
+// 仅用于为已有的路由项指定设置，避免生成一个只有 OPTIONS 的路由项。
+func (mux *Mux) find(pattern string) (*handlers.Handlers, error) {
+	hs := mux.tree.Find(pattern)
+	if hs == nil || hs.Len() == 0 {
+		return nil, fmt.Errorf("%w：%s", ErrRouteNotExists, pattern)
+	}
+
+	return hs, nil
 }
 
 // Options 将 OPTIONS 请求方法的报头 allow 值固定为指定的值。
@@ -102,10 +197,11 @@ func (mux *Mux) Handle(pattern string, h http.Handler, methods ...string) error
 // 如果想实现对处理方法的自定义，可以显示地调用 Handle 方法:
 //  Mux.Handle("/api/1", handle, http.MethodOptions)
 func (mux *Mux) Options(pattern string, allow string) *Mux {
-	err := mux.tree.SetAllow(pattern, allow)
-	if err != nil {
+	before := mux.methods(pattern)
+	if err := mux.tree.SetAllow(pattern, allow); err != nil {
 		panic(err)
 	}
+	mux.fire(&mux.addedHooks, pattern, diffMethods(mux.methods(pattern), before))
 
 	return mux
 }
@@ -147,6 +243,39 @@ func (mux *Mux) Any(pattern string, h http.Handler) *Mux {
 	return mux.handle(pattern, h)
 }
 
+// HandleStd 以 http.ServeMux 的语法添加一条路由项，比如：
+//  m.HandleStd("GET /items/{id}", h)
+//  m.HandleStd("/files/{path...}", h)
+//
+// 具体的转换规则以及与 http.ServeMux 的差别可参考 dialect.ServeMux。
+// 模式中的 GET 会同时注册 HEAD，若 HEAD 已经存在，则忽略。
+func (mux *Mux) HandleStd(pattern string, h http.Handler) error {
+	methods, p, err := dialect.ServeMux(pattern)
+	if err != nil {
+		return err
+	}
+
+	if len(methods) == 0 {
+		return mux.Handle(p, h)
+	}
+
+	if err = mux.Handle(p, h, methods[0]); err != nil {
+		return err
+	}
+
+	if len(methods) > 1 { // GET 附带的 HEAD
+		if hs := mux.tree.Find(p); hs.Handler(http.MethodHead) == nil {
+			return mux.Handle(p, h, http.MethodHead)
+		}
+	}
+	return nil
+}
+
+// HandleStdFunc 功能同 Mux.HandleStd()，但是将第二个参数从 http.Handler 换成了 http.HandlerFunc
+func (mux *Mux) HandleStdFunc(pattern string, fun http.HandlerFunc) error {
+	return mux.HandleStd(pattern, fun)
+}
+
 // HandleFunc 功能同 Mux.Handle()，但是将第二个参数从 http.Handler 换成了 http.HandlerFunc
 func (mux *Mux) HandleFunc(pattern string, fun http.HandlerFunc, methods ...string) error {
 	return mux.Handle(pattern, fun, methods...)
@@ -192,29 +321,49 @@ func (mux *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		p = cleanPath(p)
 	}
 
-	hs, ps := mux.tree.Handler(p)
+	ps := make(params.Params, 4) // 不会逃逸，仅在栈上分配
+	hs := mux.tree.RouteParams(p, ps)
+	if mux.metrics == nil {
+		mux.serve(w, r, p, ps, hs)
+		return
+	}
+
+	sw := &statusWriter{ResponseWriter: w}
+	defer mux.observe(sw, r.Method, hs, time.Now())
+	mux.serve(sw, r, p, ps, hs)
+	sw.completed = true
+}
+
+// 将请求交由 hs 中的处理函数处理，hs 为 nil 表示没有匹配的路由项。
+func (mux *Mux) serve(w http.ResponseWriter, r *http.Request, p string, ps params.Params, hs *handlers.Handlers) {
+	if mux.recovery != nil {
+		defer mux.recover(w, r, hs)
+	}
+
 	if hs == nil {
-		mux.notFound(w, r)
+		mux.notFoundHandler(p).ServeHTTP(w, r)
 		return
 	}
 
 	h := hs.Handler(r.Method)
 	if h == nil {
+		if !handlers.IsSupported(r.Method) {
+			mux.notImplementedHandler(p, hs).ServeHTTP(w, r)
+			return
+		}
+
 		w.Header().Set("Allow", hs.Options())
-		mux.methodNotAllowed(w, r)
+		mux.methodNotAllowedHandler(p, hs).ServeHTTP(w, r)
 		return
 	}
 
-	if len(ps) > 0 {
-		ctx := context.WithValue(r.Context(), params.ContextKeyParams, ps)
-		r = r.WithContext(ctx)
-	}
-
-	h.ServeHTTP(w, r)
+	mux.serveLimited(w, newRouteContext(r, ps, hs.Route()), p, hs, h)
 }
 
 // Name 为一条路由项命名。
 // URL 可以通过此属性来生成地址。
+//
+// 同一路由项有多个名称时，Route.Name() 只返回其中之一。
 func (mux *Mux) Name(name, pattern string) error {
 	mux.namesMu.Lock()
 	defer mux.namesMu.Unlock()
@@ -224,9 +373,31 @@ func (mux *Mux) Name(name, pattern string) error {
 	}
 
 	mux.names[name] = pattern
+
+	// 路由项已经存在，且未命名，则同步其名称
+	if hs := mux.tree.Find(pattern); hs != nil && hs.Route().Name() == "" {
+		hs.SetName(name)
+	}
 	return nil
 }
 
+// SetMeta 为 pattern 指定的路由项设置元数据。
+//
+// methods 为元数据所针对的请求方法，为空表示所有请求方法共用，
+// 针对具体请求方法的元数据优先级更高。
+// 在处理函数中可通过 Meta 或是 CurrentRoute 获取。
+//
+// 只能为已经存在的路由项设置元数据，否则返回 ErrRouteNotExists；
+// 路由项的所有请求方法都被删除之后，元数据也将一并失效。
+func (mux *Mux) SetMeta(pattern, key string, val interface{}, methods ...string) error {
+	hs, err := mux.find(pattern)
+	if err != nil {
+		return err
+	}
+
+	return hs.SetMeta(key, val, methods...)
+}
+
 // URL 根据参数生成地址。
 // name 为路由的名称，或是直接为路由项的定义内容；
 // params 为路由项中的参数，键名为参数名，键值为参数值。
@@ -242,6 +413,19 @@ func (mux *Mux) URL(name string, params map[string]string) (string, error) {
 	return mux.tree.URL(pattern, params)
 }
 
+// SupportedMethods 返回所有支持的请求方法。
+//
+// 不在此列表中的请求方法，无法通过 Handle 等方法注册，
+// 访问时也会被 Mux.ServeHTTP 当作 501 处理。
+func SupportedMethods() []string {
+	return handlers.Methods()
+}
+
+// IsSupportedMethod 判断 method 是否为支持的请求方法，区分大小写。
+func IsSupportedMethod(method string) bool {
+	return handlers.IsSupported(method)
+}
+
 // Params 获取路由的参数集合。详细情况可参考 params.Get
 func Params(r *http.Request) params.Params {
 	return params.Get(r)
@@ -158,6 +158,43 @@ func TestMux_Add_Remove(t *testing.T) {
 	test.matchTrue(http.MethodPost, "/api/1", http.StatusNotFound) // 404 表示整个节点都没了
 }
 
+func TestMux_NotImplemented(t *testing.T) {
+	a := assert.New(t)
+	test := newTester(a, false, false)
+
+	a.NotError(test.mux.HandleFunc("/api/1", buildFunc(http.StatusOK), http.MethodGet))
+	test.matchTrue(http.MethodGet, "/api/1", http.StatusOK)
+	test.matchTrue(http.MethodDelete, "/api/1", http.StatusMethodNotAllowed)
+	test.matchTrue("FOO", "/api/1", http.StatusNotImplemented)
+	test.matchTrue("FOO", "/api/2", http.StatusNotFound) // 路由不存在，依然是 404
+
+	// Any 也不包含不支持的请求方法
+	test.mux.Any("/api/any", buildHandler(http.StatusAccepted))
+	test.matchTrue("FOO", "/api/any", http.StatusNotImplemented)
+
+	// 自定义
+	test.mux.SetNotImplemented(buildFunc(http.StatusTeapot))
+	test.matchTrue("FOO", "/api/1", http.StatusTeapot)
+
+	// 恢复默认
+	test.mux.SetNotImplemented(nil)
+	test.matchTrue("FOO", "/api/1", http.StatusNotImplemented)
+}
+
+func TestSupportedMethods(t *testing.T) {
+	a := assert.New(t)
+
+	methods := SupportedMethods()
+	a.NotEmpty(methods)
+	for _, m := range methods {
+		a.True(IsSupportedMethod(m))
+	}
+	a.False(IsSupportedMethod("FOO"))
+
+	methods[0] = "FOO" // 修改返回值不影响原始数据
+	a.False(IsSupportedMethod("FOO"))
+}
+
 func TestMux_Options(t *testing.T) {
 	a := assert.New(t)
 	test := newTester(a, false, false)
@@ -326,3 +363,73 @@ func BenchmarkCleanPath(b *testing.B) {
 		a.True(len(ret) > 0)
 	}
 }
+
+// 与 http.ServeMux 的匹配结果进行比较
+func TestMux_HandleStd(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+	stdmux := http.NewServeMux()
+
+	patterns := map[string][]string{ // 匹配模式及其需要输出的参数
+		"GET /items/{id}":        {"id"},
+		"GET /items/new":         nil,
+		"DELETE /items/{id}":     {"id"},
+		"/items/{id}/edit":       {"id"},
+		"/files/{path...}":       {"path"},
+		"/static/":               nil,
+		"/{$}":                   nil,
+		"GET /users/{uid}/{$}":   {"uid"},
+		"GET /users/{uid}/posts": {"uid"},
+	}
+
+	for pattern, names := range patterns {
+		names := names
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Method + " "))
+			for _, name := range names {
+				w.Write([]byte(name + "=" + r.PathValue(name) + ";"))
+			}
+		})
+
+		a.NotError(srvmux.HandleStd(pattern, h))
+		stdmux.Handle(pattern, h)
+	}
+
+	requests := []struct{ method, path string }{
+		{http.MethodGet, "/items/1"},
+		{http.MethodHead, "/items/1"},
+		{http.MethodDelete, "/items/1"},
+		{http.MethodPost, "/items/1"},
+		{http.MethodGet, "/items/new"},
+		{http.MethodGet, "/items/1/2"},
+		{http.MethodGet, "/items/1/edit"},
+		{http.MethodPut, "/items/1/edit"},
+		{http.MethodGet, "/files/a/b/c.txt"},
+		{http.MethodGet, "/files/"},
+		{http.MethodGet, "/static/"},
+		{http.MethodGet, "/static/css/main.css"},
+		{http.MethodGet, "/"},
+		{http.MethodGet, "/not-exists"},
+		{http.MethodGet, "/users/5/"},
+		{http.MethodGet, "/users/5/posts"},
+		{http.MethodGet, "/users/5/posts/1"},
+	}
+
+	for _, req := range requests {
+		w1 := httptest.NewRecorder()
+		srvmux.ServeHTTP(w1, httptest.NewRequest(req.method, req.path, nil))
+
+		w2 := httptest.NewRecorder()
+		stdmux.ServeHTTP(w2, httptest.NewRequest(req.method, req.path, nil))
+
+		a.Equal(w1.Code, w2.Code, "%s %s 的状态码不同：%d 和 %d", req.method, req.path, w1.Code, w2.Code)
+		if w2.Code == http.StatusOK {
+			a.Equal(w1.Body.String(), w2.Body.String(), "%s %s 的输出不同", req.method, req.path)
+		}
+	}
+
+	// GET 之后 HEAD 已经存在
+	a.Error(srvmux.HandleStd("HEAD /items/{id}", buildHandler(1)))
+	a.Error(srvmux.HandleStd("example.com/", buildHandler(1)))
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package muxtest 提供了测试路由匹配结果的辅助函数。
+//
+// 所有的判断都基于 Mux.Match，不会真正调用处理函数，也不需要 httptest：
+//  func TestRoutes(t *testing.T) {
+//      m := mux.New(false, false, nil, nil)
+//      m.Get("/posts/{id:\\d+}", h)
+//      m.Name("post", "/posts/{id:\\d+}")
+//
+//      muxtest.New(t, m).
+//          Route("GET", "/posts/1", "post", map[string]string{"id": "1"}).
+//          Allow("/posts/1", "GET", "OPTIONS").
+//          MethodNotAllowed("POST", "/posts/1").
+//          NotFound("GET", "/posts/abc").
+//          Golden("./testdata/routes.golden")
+//  }
+package muxtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/issue9/mux"
+)
+
+var update = flag.Bool("muxtest.update", false, "根据实际的匹配结果更新 golden 文件")
+
+// Tester 用于测试 Mux 的路由匹配结果
+type Tester struct {
+	tb  testing.TB
+	mux *mux.Mux
+}
+
+// New 声明一个新的 Tester 实例
+func New(tb testing.TB, m *mux.Mux) *Tester {
+	return &Tester{
+		tb:  tb,
+		mux: m,
+	}
+}
+
+// Route 判断请求 method 和 path 能否匹配到 route 指定的路由项，且路由参数为 params。
+//
+// route 可以是路由项的名称，也可以是其匹配模式；params 为空表示没有路由参数。
+func (t *Tester) Route(method, path, route string, params map[string]string) *Tester {
+	t.tb.Helper()
+
+	m := t.mux.Match(method, path)
+	if m.Status != http.StatusOK {
+		t.tb.Errorf("%s %s 的状态码为 %d，而不是 %d", method, path, m.Status, http.StatusOK)
+		return t
+	}
+
+	if r := m.Route; r.Name() != route && r.Pattern() != route {
+		t.tb.Errorf("%s %s 匹配的路由项为 %s，而不是 %s", method, path, identity(r), route)
+	}
+
+	if !equalParams(m.Params, params) {
+		t.tb.Errorf("%s %s 的路由参数为 %v，而不是 %v", method, path, map[string]string(m.Params), params)
+	}
+
+	return t
+}
+
+// Allow 判断 path 匹配的路由项允许的请求方法是否为 methods，顺序不作要求。
+func (t *Tester) Allow(path string, methods ...string) *Tester {
+	t.tb.Helper()
+
+	m := t.mux.Match("", path)
+	if m.Route == nil {
+		t.tb.Errorf("%s 没有匹配的路由项", path)
+		return t
+	}
+
+	expected := make([]string, len(methods))
+	copy(expected, methods)
+	sort.Strings(expected)
+
+	if actual := m.Route.Methods(); strings.Join(actual, ", ") != strings.Join(expected, ", ") {
+		t.tb.Errorf("%s 允许的请求方法为 %v，而不是 %v", path, actual, expected)
+	}
+
+	return t
+}
+
+// NotFound 判断请求 method 和 path 是否返回 404
+func (t *Tester) NotFound(method, path string) *Tester {
+	t.tb.Helper()
+	t.status(method, path, http.StatusNotFound)
+	return t
+}
+
+// MethodNotAllowed 判断请求 method 和 path 是否返回 405
+func (t *Tester) MethodNotAllowed(method, path string) *Tester {
+	t.tb.Helper()
+	t.status(method, path, http.StatusMethodNotAllowed)
+	return t
+}
+
+func (t *Tester) status(method, path string, status int) {
+	t.tb.Helper()
+
+	if m := t.mux.Match(method, path); m.Status != status {
+		t.tb.Errorf("%s %s 的状态码为 %d，而不是 %d", method, path, m.Status, status)
+	}
+}
+
+// Golden 以文件 path 中的内容作为期望值，测试所有的请求。
+//
+// 文件中每一行表示一条请求及其期望的匹配结果，格式如下：
+//  GET /posts/1 => 200 /posts/{id:\d+} (post) id=1
+//  POST /posts/1 => 405 /posts/{id:\d+} (post) id=1
+//  GET /posts/abc => 404
+// => 之后依次为状态码、匹配的路由项、路由项的名称以及按名称排序的路由参数，
+// 没有的项则省略。空行和以 # 开头的行会被忽略。
+//
+// 若在执行测试时指定了 -muxtest.update 参数，则会以实际的匹配结果更新该文件，
+// 此时只需要在文件中写入请求部分，比如 GET /posts/1。
+func (t *Tester) Golden(path string) *Tester {
+	t.tb.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.tb.Fatal(err)
+		return t
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		req, expected := line, ""
+		if index := strings.Index(line, "=>"); index >= 0 {
+			req, expected = strings.TrimSpace(line[:index]), strings.TrimSpace(line[index+2:])
+		}
+
+		fields := strings.Fields(req)
+		if len(fields) != 2 {
+			t.tb.Errorf("%s 第 %d 行格式错误：%s", path, i+1, line)
+			continue
+		}
+
+		actual := result(t.mux.Match(fields[0], fields[1]))
+		if *update {
+			lines[i] = req + " => " + actual
+			continue
+		}
+
+		if actual != expected {
+			t.tb.Errorf("%s 第 %d 行：%s 的匹配结果为 %s，而不是 %s", path, i+1, req, actual, expected)
+		}
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.tb.Fatal(err)
+		}
+	}
+
+	return t
+}
+
+// 将匹配结果转换成 golden 文件中的格式
+func result(m *mux.Match) string {
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, m.Status)
+
+	if r := m.Route; r != nil && m.Status != http.StatusNotImplemented {
+		fmt.Fprint(buf, " ", r.Pattern())
+		if name := r.Name(); name != "" {
+			fmt.Fprint(buf, " (", name, ")")
+		}
+
+		keys := make([]string, 0, len(m.Params))
+		for key := range m.Params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprint(buf, " ", key, "=", m.Params[key])
+		}
+	}
+
+	return buf.String()
+}
+
+// 路由项的标识，有名称的返回名称，否则返回匹配模式。
+func identity(r *mux.Route) string {
+	if name := r.Name(); name != "" {
+		return name
+	}
+	return r.Pattern()
+}
+
+func equalParams(actual, expected map[string]string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	for key, val := range expected {
+		if v, found := actual[key]; !found || v != val {
+			return false
+		}
+	}
+	return true
+}
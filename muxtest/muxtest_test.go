@@ -0,0 +1,102 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package muxtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+)
+
+var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+// 记录错误信息的 testing.TB 实现
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatal(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func newMux(a *assert.Assertion) *mux.Mux {
+	m := mux.New(false, false, nil, nil)
+	a.NotNil(m)
+
+	m.Get("/posts/{id:\\d+}", h).
+		Get("/posts", h).
+		Post("/posts", h).
+		Get("/users/{uid}/files/{path}", h)
+	a.NotError(m.Name("post", "/posts/{id:\\d+}"))
+
+	return m
+}
+
+func TestTester(t *testing.T) {
+	a := assert.New(t)
+	m := newMux(a)
+
+	New(t, m).
+		Route(http.MethodGet, "/posts/1", "post", map[string]string{"id": "1"}).
+		Route(http.MethodGet, "/posts/1", "/posts/{id:\\d+}", map[string]string{"id": "1"}).
+		Route(http.MethodPost, "/posts", "/posts", nil).
+		Allow("/posts", http.MethodPost, http.MethodGet, http.MethodOptions).
+		NotFound(http.MethodGet, "/posts/abc").
+		MethodNotAllowed(http.MethodDelete, "/posts/1").
+		Golden("./testdata/routes.golden")
+
+	// 各类错误
+	r := &recorder{}
+	New(r, m).
+		Route(http.MethodGet, "/posts/abc", "post", nil).
+		Route(http.MethodGet, "/posts/1", "posts", map[string]string{"id": "1"}).
+		Route(http.MethodGet, "/posts/1", "post", map[string]string{"id": "2"}).
+		Allow("/posts/abc", http.MethodGet).
+		Allow("/posts", http.MethodGet).
+		NotFound(http.MethodGet, "/posts").
+		MethodNotAllowed(http.MethodGet, "/posts").
+		Golden("./testdata/not-exists.golden")
+	a.Equal(len(r.errors), 8)
+}
+
+func TestTester_Golden(t *testing.T) {
+	a := assert.New(t)
+	m := newMux(a)
+
+	dir, err := ioutil.TempDir("", "muxtest")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "routes.golden")
+
+	// 错误的期望值以及格式
+	a.NotError(ioutil.WriteFile(path, []byte("GET /posts => 404\nGET\nGET /posts/1 => 200 /posts/{id:\\d+} (post) id=1\n"), 0644))
+	r := &recorder{}
+	New(r, m).Golden(path)
+	a.Equal(len(r.errors), 2)
+
+	// 更新
+	a.NotError(ioutil.WriteFile(path, []byte("# 注释\nGET /posts\nDELETE /posts => 200\n"), 0644))
+	*update = true
+	New(t, m).Golden(path)
+	*update = false
+
+	data, err := ioutil.ReadFile(path)
+	a.NotError(err)
+	a.Equal(string(data), "# 注释\nGET /posts => 200 /posts\nDELETE /posts => 405 /posts\n")
+	New(t, m).Golden(path)
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package openapi 根据 mux.Mux 中的路由项生成 OpenAPI 3 文档。
+//
+//  doc, err := openapi.Generate(m, &openapi.Info{Title: "api", Version: "1.0.0"}, map[string]*openapi.Operation{
+//      "GET /posts/{id:\\d+}": {Summary: "获取文章", Tags: []string{"posts"}},
+//  })
+//  doc.WriteYAML(os.Stdout)
+//
+// 路由参数会被转换成 in 为 path 的参数，正则参数的表达式会被转换成 schema 的 pattern 字段。
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/issue9/mux"
+	"github.com/issue9/mux/internal/pattern"
+)
+
+// Version 生成的文档所采用的 OpenAPI 版本
+const Version = "3.0.3"
+
+// MetaKey 路由项元数据中保存 *Operation 的键名，可通过 Mux.SetMeta 等设置。
+const MetaKey = "openapi"
+
+// Document 表示 OpenAPI 文档
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    *Info               `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info 表示文档的基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem 表示某一路径下的所有操作，键名为小写的请求方法。
+type PathItem map[string]*Operation
+
+// Operation 表示对某一路径的操作
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter 表示操作的参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path、query、header 或是 cookie
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody 表示请求的内容
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"` // 键名为 mimetype
+}
+
+// Response 表示返回的内容
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"` // 键名为 mimetype
+}
+
+// MediaType 表示某一 mimetype 下的内容格式
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema 表示数据的格式
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// Generate 根据 m 中的路由项生成 OpenAPI 文档。
+//
+// meta 为各个操作的额外信息，会与自动生成的内容合并，
+// 键名为请求方法加上空格和路由项的匹配模式，比如 "GET /posts/{id:\\d+}"，
+// 也可以用路由项的名称代替匹配模式，比如 "GET post"，两者都存在时，以匹配模式为准。
+//
+// 路由项中键名为 MetaKey 的元数据若为 *Operation 类型，也会被合并，
+// 其优先级低于参数 meta。
+//
+// OPTIONS 请求不会出现在文档中。若多个路由项转换成相同的路径，
+// 比如 /posts/{id:\\d+} 和 /posts/{id}，则返回错误。
+func Generate(m *mux.Mux, info *Info, meta map[string]*Operation) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem, 50),
+	}
+	patterns := make(map[string]string, 50) // 路径与对应的路由项
+
+	for _, route := range m.Routes() {
+		path, params, err := convert(route.Pattern())
+		if err != nil {
+			return nil, err
+		}
+
+		item := make(PathItem, 5)
+		for _, method := range route.Methods() {
+			if method == http.MethodOptions {
+				continue
+			}
+
+			op := &Operation{
+				Parameters: params,
+				Responses: map[string]*Response{
+					"default": {Description: "default"},
+				},
+			}
+			if name := route.Name(); name != "" {
+				op.OperationID = strings.ToLower(method) + "_" + name
+			}
+
+			if md, found := route.Meta(method, MetaKey); found {
+				if md, ok := md.(*Operation); ok {
+					op.merge(md)
+				}
+			}
+
+			if md, found := meta[method+" "+route.Pattern()]; found {
+				op.merge(md)
+			} else if md, found := meta[method+" "+route.Name()]; found && route.Name() != "" {
+				op.merge(md)
+			}
+
+			item[strings.ToLower(method)] = op
+		}
+
+		if len(item) == 0 {
+			continue
+		}
+
+		if prev, found := patterns[path]; found {
+			return nil, fmt.Errorf("路由项 %s 和 %s 对应相同的路径 %s", prev, route.Pattern(), path)
+		}
+		patterns[path] = route.Pattern()
+		doc.Paths[path] = item
+	}
+
+	return doc, nil
+}
+
+// 将路由项的匹配模式转换成 OpenAPI 的路径格式，同时返回路径中的参数。
+func convert(p string) (string, []*Parameter, error) {
+	path, segs, err := pattern.Path(p)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var params []*Parameter
+	for _, seg := range segs {
+		param := &Parameter{
+			Name:     seg.Name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		}
+		if seg.Regexp != "" {
+			param.Schema.Pattern = "^" + seg.Regexp + "$"
+		}
+		if seg.Endpoint && seg.Regexp == "" {
+			param.Description = "匹配路径中剩余的所有内容，包括 /"
+		}
+		params = append(params, param)
+	}
+
+	return path, params, nil
+}
+
+// 将 meta 中的非零值合并到 op 中
+func (op *Operation) merge(meta *Operation) {
+	if len(meta.Tags) > 0 {
+		op.Tags = meta.Tags
+	}
+	if meta.Summary != "" {
+		op.Summary = meta.Summary
+	}
+	if meta.Description != "" {
+		op.Description = meta.Description
+	}
+	if meta.OperationID != "" {
+		op.OperationID = meta.OperationID
+	}
+	if meta.RequestBody != nil {
+		op.RequestBody = meta.RequestBody
+	}
+	if len(meta.Responses) > 0 {
+		op.Responses = meta.Responses
+	}
+	if meta.Deprecated {
+		op.Deprecated = true
+	}
+
+	// 参数以 in 和 name 作为唯一标识，相同的以 meta 为准
+	params := make([]*Parameter, 0, len(op.Parameters)+len(meta.Parameters))
+	for _, p := range op.Parameters {
+		if findParameter(meta.Parameters, p.In, p.Name) == nil {
+			params = append(params, p)
+		}
+	}
+	op.Parameters = append(params, meta.Parameters...)
+	if len(op.Parameters) == 0 {
+		op.Parameters = nil
+	}
+}
+
+func findParameter(params []*Parameter, in, name string) *Parameter {
+	for _, p := range params {
+		if p.In == in && p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON 以 JSON 格式向 w 输出文档内容
+func (doc *Document) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteYAML 以 YAML 格式向 w 输出文档内容
+func (doc *Document) WriteYAML(w io.Writer) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	v, err := parseJSON(data)
+	if err != nil { // 由 json.Marshal 生成的内容，理论上不会出错
+		return fmt.Errorf("openapi: %v", err)
+	}
+
+	return writeYAML(w, v)
+}
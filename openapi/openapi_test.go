@@ -0,0 +1,159 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+)
+
+var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+func TestConvert(t *testing.T) {
+	a := assert.New(t)
+
+	path, params, err := convert("/posts")
+	a.NotError(err).Equal(path, "/posts").Nil(params)
+
+	path, params, err = convert("/posts/{id:\\d+}/{path}")
+	a.NotError(err).Equal(path, "/posts/{id}/{path}")
+	a.Equal(params, []*Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string", Pattern: "^\\d+$"}},
+		{Name: "path", In: "path", Required: true, Schema: &Schema{Type: "string"}, Description: "匹配路径中剩余的所有内容，包括 /"},
+	})
+
+	path, params, err = convert("/posts/{id")
+	a.Error(err).Empty(path).Nil(params)
+}
+
+func TestGenerate(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", h).
+		Delete("/posts/{id:\\d+}", h).
+		Get("/posts", h).
+		Post("/posts", h)
+	a.NotError(m.Name("post", "/posts/{id:\\d+}"))
+
+	info := &Info{Title: "test", Version: "1.0.0"}
+	doc, err := Generate(m, info, map[string]*Operation{
+		"GET /posts/{id:\\d+}": {
+			Summary: "获取文章",
+			Tags:    []string{"posts"},
+			Parameters: []*Parameter{
+				{Name: "id", In: "path", Required: true, Description: "文章 ID", Schema: &Schema{Type: "integer"}},
+				{Name: "fields", In: "query"},
+			},
+		},
+		"DELETE post": {Summary: "删除文章", Deprecated: true},
+		"POST /posts": {
+			Responses: map[string]*Response{"201": {Description: "created"}},
+		},
+	})
+	a.NotError(err).NotNil(doc)
+	a.Equal(doc.OpenAPI, Version).Equal(doc.Info, info)
+	a.Equal(len(doc.Paths), 2)
+
+	post := doc.Paths["/posts/{id}"]
+	a.Equal(len(post), 2) // 不包含 OPTIONS
+	a.Equal(post["get"].Summary, "获取文章").
+		Equal(post["get"].Tags, []string{"posts"}).
+		Equal(post["get"].OperationID, "get_post").
+		Equal(len(post["get"].Parameters), 2).
+		Equal(post["get"].Parameters[0].Schema.Type, "integer").
+		Equal(post["get"].Parameters[1].Name, "fields")
+	a.Equal(post["delete"].Summary, "删除文章").
+		True(post["delete"].Deprecated).
+		Equal(post["delete"].Parameters[0].Schema.Pattern, "^\\d+$")
+
+	posts := doc.Paths["/posts"]
+	a.Equal(len(posts), 2)
+	a.Equal(posts["post"].Responses, map[string]*Response{"201": {Description: "created"}})
+	a.Equal(posts["get"].Responses, map[string]*Response{"default": {Description: "default"}})
+	a.Empty(posts["get"].OperationID).Nil(posts["get"].Parameters)
+}
+
+func TestGenerate_conflict(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", h).Get("/posts/{id}", h)
+
+	doc, err := Generate(m, &Info{Title: "test", Version: "1.0.0"}, nil)
+	a.Error(err).Nil(doc)
+
+	// 只有 OPTIONS 的路由项不会出现在文档中，也就不存在冲突
+	m = mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", h).Options("/posts/{id}", "GET")
+	doc, err = Generate(m, &Info{Title: "test", Version: "1.0.0"}, nil)
+	a.NotError(err).Equal(len(doc.Paths), 1)
+}
+
+func TestGenerate_Meta(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts", h).Post("/posts", h)
+	a.NotError(m.SetMeta("/posts", MetaKey, &Operation{Summary: "文章", Tags: []string{"posts"}}))
+	a.NotError(m.SetMeta("/posts", MetaKey, &Operation{Summary: "添加文章"}, http.MethodPost))
+
+	doc, err := Generate(m, &Info{Title: "test", Version: "1.0.0"}, map[string]*Operation{
+		"POST /posts": {Summary: "新建文章"},
+	})
+	a.NotError(err).NotNil(doc)
+
+	posts := doc.Paths["/posts"]
+	a.Equal(posts["get"].Summary, "文章").Equal(posts["get"].Tags, []string{"posts"})
+	a.Equal(posts["post"].Summary, "新建文章").Empty(posts["post"].Tags) // 参数 meta 优先
+}
+
+func TestDocument_WriteJSON(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", h)
+
+	doc, err := Generate(m, &Info{Title: "test", Version: "1.0.0"}, nil)
+	a.NotError(err).NotNil(doc)
+
+	buf := new(bytes.Buffer)
+	a.NotError(doc.WriteJSON(buf))
+
+	v := map[string]interface{}{}
+	a.NotError(json.Unmarshal(buf.Bytes(), &v))
+	a.Equal(v["openapi"], Version)
+}
+
+func TestDocument_WriteYAML(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", h)
+
+	doc, err := Generate(m, &Info{Title: "test", Version: "1.0.0"}, nil)
+	a.NotError(err).NotNil(doc)
+
+	buf := new(bytes.Buffer)
+	a.NotError(doc.WriteYAML(buf))
+	a.Equal(buf.String(), `openapi: "3.0.3"
+info:
+  title: "test"
+  version: "1.0.0"
+paths:
+  "/posts/{id}":
+    get:
+      parameters:
+        - name: "id"
+          in: "path"
+          required: true
+          schema:
+            type: "string"
+            pattern: "^\\d+$"
+      responses:
+        default:
+          description: "default"
+`)
+}
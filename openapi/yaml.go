@@ -0,0 +1,172 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// 不需要加引号的键名
+var plainKey = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.-]*$`)
+
+// 保持键名顺序的 JSON 值，用于转换成 YAML。
+type value struct {
+	object bool
+	array  bool
+	keys   []string // 仅对象时有效
+	items  []*value // 对象或数组的元素
+	scalar string   // 标量的 JSON 表示
+}
+
+// 解析 JSON 内容，保留对象中键名的原始顺序。
+func parseJSON(data []byte) (*value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return parseValue(dec)
+}
+
+func parseValue(dec *json.Decoder) (*value, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		v := &value{object: t == '{', array: t == '['}
+		for dec.More() {
+			if v.object {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v.keys = append(v.keys, key.(string))
+			}
+
+			item, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			v.items = append(v.items, item)
+		}
+
+		if _, err := dec.Token(); err != nil { // 结束符
+			return nil, err
+		}
+		return v, nil
+	case string:
+		return &value{scalar: quote(t)}, nil
+	case json.Number:
+		return &value{scalar: t.String()}, nil
+	case bool:
+		if t {
+			return &value{scalar: "true"}, nil
+		}
+		return &value{scalar: "false"}, nil
+	case nil:
+		return &value{scalar: "null"}, nil
+	default:
+		return nil, errors.New("无效的 JSON 内容")
+	}
+}
+
+// 以 JSON 字符串的格式为 s 加上双引号，JSON 的字符串同时也是合法的 YAML 字符串。
+func quote(s string) string {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s) // 字符串不会出错
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func writeYAML(w io.Writer, v *value) error {
+	buf := bufio.NewWriter(w)
+
+	switch {
+	case v.object && v.block():
+		v.writeObject(buf, 0)
+	case v.array && v.block():
+		v.writeArray(buf, 0)
+	default:
+		buf.WriteString(v.inline())
+		buf.WriteByte('\n')
+	}
+
+	return buf.Flush()
+}
+
+// 空对象、空数组以及标量的内容
+func (v *value) inline() string {
+	switch {
+	case v.object:
+		return "{}"
+	case v.array:
+		return "[]"
+	default:
+		return v.scalar
+	}
+}
+
+// 是否需要换行输出
+func (v *value) block() bool {
+	return (v.object || v.array) && len(v.items) > 0
+}
+
+// 输出对象的内容，第一行不输出缩进，由调用方决定其位置。
+func (v *value) writeObject(w *bufio.Writer, indent int) {
+	for i, key := range v.keys {
+		if i > 0 {
+			w.WriteString(strings.Repeat("  ", indent))
+		}
+
+		if !plainKey.MatchString(key) {
+			key = quote(key)
+		}
+		w.WriteString(key)
+		w.WriteByte(':')
+
+		item := v.items[i]
+		switch {
+		case item.object && item.block():
+			w.WriteByte('\n')
+			w.WriteString(strings.Repeat("  ", indent+1))
+			item.writeObject(w, indent+1)
+		case item.array && item.block():
+			w.WriteByte('\n')
+			w.WriteString(strings.Repeat("  ", indent+1))
+			item.writeArray(w, indent+1)
+		default:
+			w.WriteByte(' ')
+			w.WriteString(item.inline())
+			w.WriteByte('\n')
+		}
+	}
+}
+
+// 输出数组的内容，第一行不输出缩进，由调用方决定其位置。
+func (v *value) writeArray(w *bufio.Writer, indent int) {
+	for i, item := range v.items {
+		if i > 0 {
+			w.WriteString(strings.Repeat("  ", indent))
+		}
+		w.WriteString("- ")
+
+		switch {
+		case item.object && item.block():
+			item.writeObject(w, indent+1)
+		case item.array && item.block():
+			item.writeArray(w, indent+1)
+		default:
+			w.WriteString(item.inline())
+			w.WriteByte('\n')
+		}
+	}
+}
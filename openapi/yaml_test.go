@@ -0,0 +1,51 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestWriteYAML(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(json, yaml string) {
+		v, err := parseJSON([]byte(json))
+		a.NotError(err).NotNil(v)
+
+		buf := new(bytes.Buffer)
+		a.NotError(writeYAML(buf, v))
+		a.Equal(buf.String(), yaml)
+	}
+
+	test(`5`, "5\n")
+	test(`"<str>"`, "\"<str>\"\n")
+	test(`{}`, "{}\n")
+	test(`[]`, "[]\n")
+	test(`{"b":1,"a":[1,"2",true,null],"c":{},"d":[]}`, `b: 1
+a:
+  - 1
+  - "2"
+  - true
+  - null
+c: {}
+d: []
+`)
+	test(`[{"a":1,"b":{"c":2}},[1,2],{}]`, `- a: 1
+  b:
+    c: 2
+- - 1
+  - 2
+- {}
+`)
+	test(`{"a b":"x\ny"}`, `"a b": "x\ny"
+`)
+
+	v, err := parseJSON([]byte(`{"a":}`))
+	a.Error(err).Nil(v)
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package params
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 结构体中指定参数名称的标签名
+const (
+	tagName      = "param"
+	queryTagName = "query"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// FieldError 表示绑定单个字段时的错误
+type FieldError struct {
+	Field string // 字段名，嵌套的字段以 . 分隔
+	Param string // 参数名
+	Err   error  // 参数不存在时为 ErrParamNotExists，否则为 *ParamError
+}
+
+// BindError 表示 Bind 中所有字段的错误信息
+type BindError []*FieldError
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("字段 %s 绑定参数 %s 时出错：%s", err.Field, err.Param, err.Err.Error())
+}
+
+func (err BindError) Error() string {
+	msgs := make([]string, 0, len(err))
+	for _, e := range err {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "；")
+}
+
+// Bind 将请求 r 中的路由参数写入到 v 中，相当于 Get(r).Bind(v)。
+func Bind(r *http.Request, v interface{}) error {
+	return Get(r).Bind(v)
+}
+
+// BindQuery 将请求 r 中的查询参数写入到 v 中。
+//
+// 只有带 query 标签的字段才会被写入，同一参数有多个值时，只取第一个。
+// 与 Bind 不同，不存在的查询参数会被忽略，字段保持原来的值，
+// 其它规则与 Bind 相同。
+func BindQuery(r *http.Request, v interface{}) error {
+	query := r.URL.Query()
+
+	return bind(v, &binder{
+		tag: queryTagName,
+		lookup: func(key string) (string, bool) {
+			vals, found := query[key]
+			if !found || len(vals) == 0 {
+				return "", false
+			}
+			return vals[0], true
+		},
+	})
+}
+
+// Bind 将参数写入到 v 中。
+//
+// v 必须为结构体指针，只有带 param 标签的字段才会被写入，标签值为参数名，
+// 标签值为空时以字段名作为参数名，比如：
+//  type post struct {
+//      ID   int64     `param:"id"`
+//      Date time.Time `param:"date"` // 以 RFC3339 格式解析
+//  }
+// 标签值为 - 的字段以及未导出的字段会被忽略，没有标签的嵌入结构体会被展开。
+//
+// 支持的字段类型有：各类整数、浮点数、bool、string、time.Time、time.Duration、
+// 实现了 encoding.TextUnmarshaler 接口的类型，以及以上类型的指针。
+//
+// 若有字段无法写入，比如参数不存在或是类型转换出错，
+// 会继续处理其它的字段，最终返回包含所有错误信息的 BindError。
+func (p Params) Bind(v interface{}) error {
+	return bind(v, &binder{
+		tag:      tagName,
+		required: true,
+		lookup: func(key string) (string, bool) {
+			val, found := p[key]
+			return val, found
+		},
+	})
+}
+
+// 将 lookup 返回的值写入到带 tag 标签的字段中。
+type binder struct {
+	tag      string
+	required bool // 值不存在时是否作为错误
+	lookup   func(key string) (string, bool)
+}
+
+func bind(v interface{}, b *binder) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("参数 v 必须为结构体指针")
+	}
+
+	var errs BindError
+	b.bindStruct(rv.Elem(), "", &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (b *binder) bindStruct(rv reflect.Value, prefix string, errs *BindError) {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, hasTag := field.Tag.Lookup(b.tag)
+
+		if !hasTag {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				b.bindStruct(rv.Field(i), prefix+field.Name+".", errs)
+			}
+			continue
+		}
+
+		if tag == "-" || field.PkgPath != "" { // 忽略或是未导出的字段
+			continue
+		}
+
+		if tag == "" {
+			tag = field.Name
+		}
+
+		str, found := b.lookup(tag)
+		if !found {
+			if b.required {
+				*errs = append(*errs, &FieldError{Field: prefix + field.Name, Param: tag, Err: ErrParamNotExists})
+			}
+			continue
+		}
+
+		if err := setValue(rv.Field(i), str); err != nil {
+			*errs = append(*errs, &FieldError{
+				Field: prefix + field.Name,
+				Param: tag,
+				Err:   &ParamError{Name: tag, Value: str, Type: field.Type.String(), Err: err},
+			})
+		}
+	}
+}
+
+// 将 str 转换成 v 的类型并写入 v
+func setValue(v reflect.Value, str string) error {
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), str); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	// time.Time 也实现了 encoding.TextUnmarshaler，以 RFC3339 格式解析。
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(val)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		v.SetBool(val)
+	default:
+		return fmt.Errorf("不支持的类型 %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package params
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+type embedded struct {
+	Page uint8 `param:"page"`
+}
+
+type bindObject struct {
+	embedded
+	ID       int64         `param:"id"`
+	Size     *int          `param:"size"`
+	Rate     float32       `param:"rate"`
+	Enabled  bool          `param:"enabled"`
+	Name     string        `param:""`
+	Created  time.Time     `param:"created"`
+	Timeout  time.Duration `param:"timeout"`
+	IP       net.IP        `param:"ip"`
+	Ignore   string        `param:"-"`
+	NoTag    string
+	internal int `param:"internal"`
+}
+
+func TestParams_Bind(t *testing.T) {
+	a := assert.New(t)
+
+	p := Params{
+		"page":     "3",
+		"id":       "-5",
+		"size":     "10",
+		"rate":     "0.5",
+		"enabled":  "true",
+		"Name":     "name",
+		"created":  "2017-01-02T15:04:05Z",
+		"timeout":  "1m",
+		"ip":       "127.0.0.1",
+		"Ignore":   "ignore",
+		"NoTag":    "notag",
+		"internal": "1",
+	}
+
+	obj := &bindObject{}
+	a.NotError(p.Bind(obj))
+	a.Equal(obj.Page, 3).
+		Equal(obj.ID, -5).
+		Equal(*obj.Size, 10).
+		Equal(obj.Rate, 0.5).
+		True(obj.Enabled).
+		Equal(obj.Name, "name").
+		Equal(obj.Created, time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)).
+		Equal(obj.Timeout, time.Minute).
+		Equal(obj.IP.String(), "127.0.0.1").
+		Empty(obj.Ignore).
+		Empty(obj.NoTag).
+		Equal(obj.internal, 0)
+
+	// 多个字段出错
+	p = Params{
+		"page":    "256",
+		"id":      "-5",
+		"size":    "x",
+		"rate":    "0.5",
+		"enabled": "true",
+		"Name":    "name",
+		"created": "2017-01-02",
+		"timeout": "1m",
+		"ip":      "127.0.0.1",
+	}
+	err := p.Bind(&bindObject{})
+	errs, ok := err.(BindError)
+	a.True(ok).Equal(len(errs), 3)
+	a.Equal(errs[0].Field, "embedded.Page").Equal(errs[0].Param, "page")
+	a.Equal(errs[1].Field, "Size").Equal(errs[1].Param, "size")
+	a.Equal(errs[2].Field, "Created").Equal(errs[2].Param, "created")
+	a.NotEmpty(err.Error())
+
+	// 参数不存在
+	err = Params{}.Bind(&struct {
+		ID int `param:"id"`
+	}{})
+	errs, ok = err.(BindError)
+	a.True(ok).Equal(len(errs), 1).Equal(errs[0].Err, ErrParamNotExists)
+
+	// 不支持的类型
+	err = Params{"id": "1"}.Bind(&struct {
+		ID []int `param:"id"`
+	}{})
+	errs, ok = err.(BindError)
+	a.True(ok).Equal(len(errs), 1)
+
+	// 非结构体指针
+	a.Error(p.Bind(bindObject{}))
+	a.Error(p.Bind((*bindObject)(nil)))
+	a.Error(p.Bind(new(int)))
+}
+
+func TestBind(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := http.NewRequest(http.MethodGet, "/posts/1", nil)
+	a.NotError(err).NotNil(r)
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyParams, Params{"id": "1"}))
+
+	obj := &struct {
+		ID int `param:"id"`
+	}{}
+	a.NotError(Bind(r, obj))
+	a.Equal(obj.ID, 1)
+}
+
+func TestBindQuery(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := http.NewRequest(http.MethodGet, "/posts?page=2&size=10&size=20&tag=go", nil)
+	a.NotError(err).NotNil(r)
+
+	obj := &struct {
+		Page  int    `query:"page"`
+		Size  *int   `query:"size"`
+		Tag   string `query:""`
+		Order string `query:"order"`
+		ID    int    `param:"id"`
+	}{Order: "desc"}
+	a.NotError(BindQuery(r, obj))
+	a.Equal(obj.Page, 2).
+		Equal(*obj.Size, 10).
+		Empty(obj.Tag). // 标签值为空时，以字段名 Tag 作为参数名
+		Equal(obj.Order, "desc").
+		Equal(obj.ID, 0)
+
+	// 类型错误
+	r, err = http.NewRequest(http.MethodGet, "/posts?page=abc", nil)
+	a.NotError(err).NotNil(r)
+	err = BindQuery(r, obj)
+	a.ErrorType(err, BindError{})
+	errs := err.(BindError)
+	a.Equal(len(errs), 1).Equal(errs[0].Param, "page")
+
+	a.Error(BindQuery(r, new(int)))
+}
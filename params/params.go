@@ -7,6 +7,7 @@ package params
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 )
@@ -19,6 +20,26 @@ const ContextKeyParams contextKey = 0
 // ErrParamNotExists 表示地址参数中并不存在该名称的值。
 var ErrParamNotExists = errors.New("不存在该参数")
 
+// ParamError 表示参数不存在或是参数值无法转换成指定类型时的错误。
+type ParamError struct {
+	Name  string // 参数名
+	Value string // 参数的原始值，参数不存在时为空
+	Type  string // 需要转换的目标类型
+	Err   error  // 具体的错误信息，参数不存在时为 ErrParamNotExists，否则一般为 strconv 返回的错误
+}
+
+func (err *ParamError) Error() string {
+	if err.Err == ErrParamNotExists {
+		return fmt.Sprintf("参数 %s 不存在", err.Name)
+	}
+	return fmt.Sprintf("参数 %s 的值 %s 无法转换成 %s 类型：%s", err.Name, err.Value, err.Type, err.Err.Error())
+}
+
+// Unwrap 返回具体的错误信息，方便 errors.Is 等函数的判断。
+func (err *ParamError) Unwrap() error {
+	return err.Err
+}
+
 // Params 获取和转换路由中的参数信息。
 type Params map[string]string
 
@@ -27,6 +48,10 @@ type Params map[string]string
 // 以下情况两个参数都会返回 nil：
 //  非正则和命名路由；
 //  正则路由，但是所有匹配参数都是未命名的；
+//
+// 由 mux.Mux 分发的请求，路由参数保存在 http.Request.PathValue 中，
+// 首次调用时从中生成 Params 实例，之后的调用返回同一实例，
+// 修改返回值不会影响 PathValue 中的参数，但会影响之后 Get 的返回值。
 func Get(r *http.Request) Params {
 	params := r.Context().Value(ContextKeyParams)
 	if params == nil {
@@ -45,11 +70,12 @@ func (p Params) Exists(key string) bool {
 
 // String 获取地址参数中的名为 key 的变量，并将其转换成 string
 //
-// 当参数不存在时，返回 ErrParamNotExists 错误。
+// 当参数不存在时，返回 *ParamError，
+// 可以通过 errors.Is(err, ErrParamNotExists) 判断。
 func (p Params) String(key string) (string, error) {
 	v, found := p[key]
 	if !found {
-		return "", ErrParamNotExists
+		return "", &ParamError{Name: key, Type: "string", Err: ErrParamNotExists}
 	}
 
 	return v, nil
@@ -57,6 +83,9 @@ func (p Params) String(key string) (string, error) {
 
 // MustString 获取地址参数中的名为 key 的变量，并将其转换成 string，
 // 若不存在或是无法转换则返回 def。
+//
+// 有意忽略了具体的错误信息，需要通过 *ParamError 向客户端报告错误的，
+// 包括参数不存在的情况，应该使用 Params.String。
 func (p Params) MustString(key, def string) string {
 	v, found := p[key]
 	if !found {
@@ -68,18 +97,26 @@ func (p Params) MustString(key, def string) string {
 
 // Int 获取地址参数中的名为 key 的变量，并将其转换成 int64
 //
-// 当参数不存在时，返回 ErrParamNotExists 错误。
+// 当参数不存在或是无法转换时，返回 *ParamError，
+// 参数不存在时，可以通过 errors.Is(err, ErrParamNotExists) 判断。
 func (p Params) Int(key string) (int64, error) {
 	str, found := p[key]
 	if !found {
-		return 0, ErrParamNotExists
+		return 0, &ParamError{Name: key, Type: "int64", Err: ErrParamNotExists}
 	}
 
-	return strconv.ParseInt(str, 10, 64)
+	val, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return val, &ParamError{Name: key, Value: str, Type: "int64", Err: err}
+	}
+	return val, nil
 }
 
 // MustInt 获取地址参数中的名为 key 的变量，并将其转换成 int64，
 // 若不存在或是无法转换则返回 def。
+//
+// 有意忽略了具体的错误信息，需要通过 *ParamError 向客户端报告错误的，
+// 包括参数不存在的情况，应该使用 Params.Int。
 func (p Params) MustInt(key string, def int64) int64 {
 	str, found := p[key]
 	if !found {
@@ -95,18 +132,26 @@ func (p Params) MustInt(key string, def int64) int64 {
 
 // Uint 获取地址参数中的名为 key 的变量，并将其转换成 uint64
 //
-// 当参数不存在时，返回 ErrParamNotExists 错误。
+// 当参数不存在或是无法转换时，返回 *ParamError，
+// 参数不存在时，可以通过 errors.Is(err, ErrParamNotExists) 判断。
 func (p Params) Uint(key string) (uint64, error) {
 	str, found := p[key]
 	if !found {
-		return 0, ErrParamNotExists
+		return 0, &ParamError{Name: key, Type: "uint64", Err: ErrParamNotExists}
 	}
 
-	return strconv.ParseUint(str, 10, 64)
+	val, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return val, &ParamError{Name: key, Value: str, Type: "uint64", Err: err}
+	}
+	return val, nil
 }
 
 // MustUint 获取地址参数中的名为 key 的变量，并将其转换成 uint64，
 // 若不存在或是无法转换则返回 def。
+//
+// 有意忽略了具体的错误信息，需要通过 *ParamError 向客户端报告错误的，
+// 包括参数不存在的情况，应该使用 Params.Uint。
 func (p Params) MustUint(key string, def uint64) uint64 {
 	str, found := p[key]
 	if !found {
@@ -122,18 +167,26 @@ func (p Params) MustUint(key string, def uint64) uint64 {
 
 // Bool 获取地址参数中的名为 key 的变量，并将其转换成 bool
 //
-// 当参数不存在时，返回 ErrParamNotExists 错误。
+// 当参数不存在或是无法转换时，返回 *ParamError，
+// 参数不存在时，可以通过 errors.Is(err, ErrParamNotExists) 判断。
 func (p Params) Bool(key string) (bool, error) {
 	str, found := p[key]
 	if !found {
-		return false, ErrParamNotExists
+		return false, &ParamError{Name: key, Type: "bool", Err: ErrParamNotExists}
 	}
 
-	return strconv.ParseBool(str)
+	val, err := strconv.ParseBool(str)
+	if err != nil {
+		return val, &ParamError{Name: key, Value: str, Type: "bool", Err: err}
+	}
+	return val, nil
 }
 
 // MustBool 获取地址参数中的名为 key 的变量，并将其转换成 bool，
 // 若不存在或是无法转换则返回 def。
+//
+// 有意忽略了具体的错误信息，需要通过 *ParamError 向客户端报告错误的，
+// 包括参数不存在的情况，应该使用 Params.Bool。
 func (p Params) MustBool(key string, def bool) bool {
 	str, found := p[key]
 	if !found {
@@ -149,18 +202,26 @@ func (p Params) MustBool(key string, def bool) bool {
 
 // Float 获取地址参数中的名为 key 的变量，并将其转换成 Float64
 //
-// 当参数不存在时，返回 ErrParamNotExists 错误。
+// 当参数不存在或是无法转换时，返回 *ParamError，
+// 参数不存在时，可以通过 errors.Is(err, ErrParamNotExists) 判断。
 func (p Params) Float(key string) (float64, error) {
 	str, found := p[key]
 	if !found {
-		return 0, ErrParamNotExists
+		return 0, &ParamError{Name: key, Type: "float64", Err: ErrParamNotExists}
 	}
 
-	return strconv.ParseFloat(str, 64)
+	val, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return val, &ParamError{Name: key, Value: str, Type: "float64", Err: err}
+	}
+	return val, nil
 }
 
 // MustFloat 获取地址参数中的名为 key 的变量，并将其转换成 float64，
 // 若不存在或是无法转换则返回 def。
+//
+// 有意忽略了具体的错误信息，需要通过 *ParamError 向客户端报告错误的，
+// 包括参数不存在的情况，应该使用 Params.Float。
 func (p Params) MustFloat(key string, def float64) float64 {
 	str, found := p[key]
 	if !found {
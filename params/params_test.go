@@ -6,7 +6,9 @@ package params
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/issue9/assert"
@@ -52,7 +54,10 @@ func TestParams_String(t *testing.T) {
 
 	// 不存在
 	val, err = ps.String("k5")
-	a.ErrorType(err, ErrParamNotExists).Equal(val, "")
+	a.True(errors.Is(err, ErrParamNotExists)).Equal(val, "")
+	pe, ok := err.(*ParamError)
+	a.True(ok).Equal(pe.Name, "k5").Equal(pe.Type, "string")
+	a.Equal(pe.Error(), "参数 k5 不存在")
 	a.False(ps.Exists("k5"))
 	a.Equal(ps.MustString("k5", "-10"), "-10")
 }
@@ -76,7 +81,7 @@ func TestParams_Int(t *testing.T) {
 
 	// 不存在
 	val, err = ps.Int("k5")
-	a.ErrorType(err, ErrParamNotExists).Equal(val, 0)
+	a.True(errors.Is(err, ErrParamNotExists)).Equal(val, 0)
 	a.Equal(ps.MustInt("k5", -10), -10)
 }
 
@@ -105,7 +110,7 @@ func TestParams_Uint(t *testing.T) {
 
 	// 不存在
 	val, err = ps.Uint("k5")
-	a.ErrorType(err, ErrParamNotExists).Equal(val, 0)
+	a.True(errors.Is(err, ErrParamNotExists)).Equal(val, 0)
 	a.Equal(ps.MustUint("k5", 10), 10)
 }
 
@@ -133,7 +138,7 @@ func TestParams_Bool(t *testing.T) {
 
 	// 不存在
 	val, err = ps.Bool("k5")
-	a.ErrorType(err, ErrParamNotExists).False(val)
+	a.True(errors.Is(err, ErrParamNotExists)).False(val)
 	a.True(ps.MustBool("k5", true))
 }
 
@@ -161,6 +166,35 @@ func TestParams_Float(t *testing.T) {
 
 	// 不存在
 	val, err = ps.Float("k5")
-	a.ErrorType(err, ErrParamNotExists).Equal(val, 0.0)
+	a.True(errors.Is(err, ErrParamNotExists)).Equal(val, 0.0)
 	a.Equal(ps.MustFloat("k5", -10.0), -10.0)
 }
+
+func TestParamError(t *testing.T) {
+	a := assert.New(t)
+	p := Params{"int": "abc", "uint": "-1", "bool": "abc", "float": "abc"}
+
+	test := func(err error, name, typ string) {
+		pe, ok := err.(*ParamError)
+		a.True(ok).
+			Equal(pe.Name, name).
+			Equal(pe.Value, p[name]).
+			Equal(pe.Type, typ).
+			NotNil(pe.Unwrap()).
+			NotEmpty(pe.Error())
+	}
+
+	_, err := p.Int("int")
+	test(err, "int", "int64")
+	a.Equal(err.(*ParamError).Err.(*strconv.NumError).Err, strconv.ErrSyntax)
+
+	_, err = p.Uint("uint")
+	test(err, "uint", "uint64")
+
+	_, err = p.Bool("bool")
+	test(err, "bool", "bool")
+
+	_, err = p.Float("float")
+	test(err, "float", "float64")
+	a.Equal(err.(*ParamError).Err.(*strconv.NumError).Err, strconv.ErrSyntax)
+}
@@ -4,7 +4,13 @@
 
 package mux
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"github.com/issue9/mux/internal/handlers"
+	"github.com/issue9/mux/internal/tree"
+)
 
 // Prefix 可以将具有统一前缀的路由项集中在一起操作。
 //  p := srv.Prefix("/api")
@@ -15,6 +21,16 @@ type Prefix struct {
 	prefix string
 }
 
+// 由 Prefix 指定的错误处理函数，为空表示使用上一级的处理函数。
+type prefixHandlers struct {
+	prefix           *tree.Prefix
+	notFound         http.HandlerFunc
+	methodNotAllowed http.HandlerFunc
+	notImplemented   http.HandlerFunc
+	timeout          time.Duration
+	maxBodySize      int64
+}
+
 // Options 手动指定 OPTIONS 请求方法的值。具体说明可参考 Mux.Options 方法。
 func (p *Prefix) Options(pattern string, allow string) *Prefix {
 	p.mux.Options(p.prefix+pattern, allow)
@@ -119,7 +135,68 @@ func (p *Prefix) Remove(pattern string, methods ...string) *Prefix {
 //  p2 := mux.Prefix("prefix")
 //  p2.Clean() 将同时清除 p1 的内容，因为有相同的前缀。
 func (p *Prefix) Clean() *Prefix {
-	p.mux.tree.Clean(p.prefix)
+	p.mux.clean(p.prefix)
+	return p
+}
+
+// SetNotFound 指定以 Prefix.prefix 开头的路径的 404 处理方式，
+// 为 nil 时表示使用上一级的处理方式。
+//
+// 当有多个 Prefix 与路径匹配时，采用最深的那个；都不匹配时采用 New() 中指定的值。
+// 前缀按路径的分段进行比较，所以 "/api" 不会匹配 "/apiv2"；
+// 若 Prefix.prefix 中包含参数，则参数需要与路径中相应的部分匹配，
+// 比如 "/p/{version:\\d+}" 匹配 "/p/1/users"，但不匹配 "/p/users"。
+// Prefix.prefix 的语法错误会导致 panic。
+//
+// 相同前缀的 Prefix 实例共享同一组处理函数。
+func (p *Prefix) SetNotFound(h http.HandlerFunc) *Prefix {
+	p.mux.setPrefixHandler(p.prefix, func(ph *prefixHandlers) {
+		ph.notFound = h
+	})
+	return p
+}
+
+// SetMethodNotAllowed 指定以 Prefix.prefix 开头的路径的 405 处理方式，
+// 为 nil 时表示使用上一级的处理方式。
+//
+// 调用此方法前，会设置 Allow 报头，其它说明可参考 Prefix.SetNotFound。
+func (p *Prefix) SetMethodNotAllowed(h http.HandlerFunc) *Prefix {
+	p.mux.setPrefixHandler(p.prefix, func(ph *prefixHandlers) {
+		ph.methodNotAllowed = h
+	})
+	return p
+}
+
+// SetNotImplemented 指定以 Prefix.prefix 开头的路径的 501 处理方式，
+// 为 nil 时表示使用上一级的处理方式。
+//
+// 其它说明可参考 Prefix.SetNotFound。
+func (p *Prefix) SetNotImplemented(h http.HandlerFunc) *Prefix {
+	p.mux.setPrefixHandler(p.prefix, func(ph *prefixHandlers) {
+		ph.notImplemented = h
+	})
+	return p
+}
+
+// SetTimeout 指定以 Prefix.prefix 开头的路由项的处理时限，为 0 时表示使用上一级的设置。
+//
+// 路由项的设置优先于 Prefix 的设置，具体说明可参考 Mux.SetTimeout，
+// 前缀的匹配规则可参考 Prefix.SetNotFound。
+func (p *Prefix) SetTimeout(timeout time.Duration) *Prefix {
+	p.mux.setPrefixHandler(p.prefix, func(ph *prefixHandlers) {
+		ph.timeout = timeout
+	})
+	return p
+}
+
+// SetMaxBodySize 指定以 Prefix.prefix 开头的路由项的请求报文的最大值，为 0 时表示使用上一级的设置。
+//
+// 路由项的设置优先于 Prefix 的设置，具体说明可参考 Mux.SetMaxBodySize，
+// 前缀的匹配规则可参考 Prefix.SetNotFound。
+func (p *Prefix) SetMaxBodySize(size int64) *Prefix {
+	p.mux.setPrefixHandler(p.prefix, func(ph *prefixHandlers) {
+		ph.maxBodySize = size
+	})
 	return p
 }
 
@@ -129,6 +206,23 @@ func (p *Prefix) Name(name, pattern string) error {
 	return p.mux.Name(name, p.prefix+pattern)
 }
 
+// Names 相当于 Mux.Names() 的简易写法。
+//
+// 名称由 Mux 统一管理，所以返回的是所有路由项的名称，而不仅仅是以 prefix 开头的。
+func (p *Prefix) Names() map[string]string {
+	return p.mux.Names()
+}
+
+// Route 相当于 Mux.Route(prefix+pattern) 的简易写法
+func (p *Prefix) Route(pattern string) *Route {
+	return p.mux.Route(p.prefix + pattern)
+}
+
+// SetMeta 相当于 Mux.SetMeta(prefix+pattern, key, val, methods...) 的简易写法
+func (p *Prefix) SetMeta(pattern, key string, val interface{}, methods ...string) error {
+	return p.mux.SetMeta(p.prefix+pattern, key, val, methods...)
+}
+
 // URL 根据参数生成地址。
 // name 为路由的名称，或是直接为路由项的定义内容，
 // 若 name 作为路由项定义，会加上 Prefix.prefix 作为前缀；
@@ -169,3 +263,83 @@ func (mux *Mux) Prefix(prefix string) *Prefix {
 func (p *Prefix) Mux() *Mux {
 	return p.mux
 }
+
+// 获取与 prefix 对应的 prefixHandlers 实例，并通过 set 修改其内容。
+func (mux *Mux) setPrefixHandler(prefix string, set func(*prefixHandlers)) {
+	mux.prefixesMu.Lock()
+	defer mux.prefixesMu.Unlock()
+
+	for _, ph := range mux.prefixes {
+		if ph.prefix.Pattern() == prefix {
+			set(ph)
+			return
+		}
+	}
+
+	matcher, err := tree.NewPrefix(prefix)
+	if err != nil {
+		panic(err)
+	}
+
+	ph := &prefixHandlers{prefix: matcher}
+	set(ph)
+
+	// 按长度倒序插入，保证最深的前缀排在最前面。
+	index := len(mux.prefixes)
+	for i, item := range mux.prefixes {
+		if len(item.prefix.Pattern()) < len(prefix) {
+			index = i
+			break
+		}
+	}
+	mux.prefixes = append(mux.prefixes, nil)
+	copy(mux.prefixes[index+1:], mux.prefixes[index:])
+	mux.prefixes[index] = ph
+}
+
+// 从与 path 匹配的前缀中，由深至浅查找第一个由 get 返回的非空的处理函数，
+// 若都不存在，则返回 def。
+func (mux *Mux) prefixHandler(path string, def http.HandlerFunc, get func(*prefixHandlers) http.HandlerFunc) http.HandlerFunc {
+	mux.prefixesMu.RLock()
+	defer mux.prefixesMu.RUnlock()
+
+	for _, ph := range mux.prefixes {
+		if !ph.prefix.Match(path) {
+			continue
+		}
+
+		if h := get(ph); h != nil {
+			return h
+		}
+	}
+
+	return def
+}
+
+func (mux *Mux) notFoundHandler(path string) http.Handler {
+	return mux.prefixHandler(path, mux.notFound, func(ph *prefixHandlers) http.HandlerFunc {
+		return ph.notFound
+	})
+}
+
+// 若节点 hs 指定了 405 处理函数，则优先使用。
+func (mux *Mux) methodNotAllowedHandler(path string, hs *handlers.Handlers) http.Handler {
+	if h := hs.MethodNotAllowed(); h != nil {
+		return h
+	}
+
+	return mux.prefixHandler(path, mux.methodNotAllowed, func(ph *prefixHandlers) http.HandlerFunc {
+		return ph.methodNotAllowed
+	})
+}
+
+// 若节点 hs 指定了 501 处理函数，则优先使用。
+func (mux *Mux) notImplementedHandler(path string, hs *handlers.Handlers) http.Handler {
+	if h := hs.NotImplemented(); h != nil {
+		return h
+	}
+
+	return mux.prefixHandler(path, mux.notImplemented, func(ph *prefixHandlers) http.HandlerFunc {
+		return ph.notImplemented
+	})
+}
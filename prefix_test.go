@@ -90,3 +90,66 @@ func TestPrefix_Prefix(t *testing.T) {
 	pp = p.Prefix("/abc")
 	a.Equal(pp.prefix, "/abc")
 }
+
+func TestPrefix_ErrorHandlers(t *testing.T) {
+	a := assert.New(t)
+	test := newTester(a, false, false)
+
+	api := test.prefix("/api")
+	v2 := api.Prefix("/v2")
+	api.Get("/users", buildHandler(1))
+	v2.Get("/users", buildHandler(2))
+	test.mux.Get("/users", buildHandler(3))
+
+	// 未指定，使用默认值
+	test.matchTrue(http.MethodGet, "/api/not-exists", http.StatusNotFound)
+	test.matchTrue(http.MethodPost, "/api/users", http.StatusMethodNotAllowed)
+	test.matchTrue("FOO", "/api/users", http.StatusNotImplemented)
+
+	api.SetNotFound(buildFunc(710)).
+		SetMethodNotAllowed(buildFunc(711)).
+		SetNotImplemented(buildFunc(712))
+	test.matchTrue(http.MethodGet, "/api/not-exists", 710)
+	test.matchTrue(http.MethodPost, "/api/users", 711)
+	test.matchTrue("FOO", "/api/users", 712)
+	test.matchTrue(http.MethodGet, "/api/v2/not-exists", 710) // 继承自 /api
+	test.matchTrue(http.MethodPost, "/api/v2/users", 711)
+
+	// 不受影响的路径
+	test.matchTrue(http.MethodGet, "/not-exists", http.StatusNotFound)
+	test.matchTrue(http.MethodPost, "/users", http.StatusMethodNotAllowed)
+
+	// 更深层的前缀优先
+	v2.SetNotFound(buildFunc(720))
+	test.matchTrue(http.MethodGet, "/api/v2/not-exists", 720)
+	test.matchTrue(http.MethodPost, "/api/v2/users", 711) // 未指定 405，依然继承自 /api
+	test.matchTrue(http.MethodGet, "/api/not-exists", 710)
+
+	// 相同前缀的实例共享处理函数
+	test.prefix("/api/v2").SetNotFound(buildFunc(730))
+	test.matchTrue(http.MethodGet, "/api/v2/not-exists", 730)
+
+	// 取消
+	v2.SetNotFound(nil)
+	test.matchTrue(http.MethodGet, "/api/v2/not-exists", 710)
+
+	// 按路径的分段比较
+	test.matchTrue(http.MethodGet, "/apiv2/not-exists", http.StatusNotFound)
+	test.matchTrue(http.MethodGet, "/apiv2", http.StatusNotFound)
+	test.matchTrue(http.MethodGet, "/api", 710)
+
+	// 带参数的前缀，参数需要与路径匹配
+	test.prefix("/p/{version:\\d+}").SetNotFound(buildFunc(740))
+	test.matchTrue(http.MethodGet, "/p/1/not-exists", 740)
+	test.matchTrue(http.MethodGet, "/p/1", 740)
+	test.matchTrue(http.MethodGet, "/p/", http.StatusNotFound)
+	test.matchTrue(http.MethodGet, "/p/abc/not-exists", http.StatusNotFound)
+	test.prefix("/n/{name}/users").SetNotFound(buildFunc(750))
+	test.matchTrue(http.MethodGet, "/n/abc/users/1", 750)
+	test.matchTrue(http.MethodGet, "/n/abc/posts", http.StatusNotFound)
+
+	// 语法错误
+	a.Panic(func() {
+		test.prefix("/p/{version").SetNotFound(buildFunc(760))
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/mux/params"
+)
+
+// 几种错误信息的输出格式
+const (
+	problemContentType = "application/problem+json"
+	jsonContentType    = "application/json"
+	textContentType    = "text/plain"
+)
+
+// Problem 表示 RFC7807 中定义的错误信息。
+type Problem struct {
+	// 错误类型的 URI，默认为 about:blank，表示没有额外的含义，
+	// 此时 Title 应该与状态码的描述相同。
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// 扩展字段，仅在 405 时有值，表示当前路由项允许的请求方法。
+	Allow []string `json:"allow,omitempty"`
+
+	// 扩展字段，由 WriteParamError 输出，表示各个参数的错误信息。
+	InvalidParams []*InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam 表示 Problem 中单个参数的错误信息
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// NewProblem 根据状态码声明一个 Problem 实例。
+//
+// Instance 为当前请求的路径，Allow 取自 w 中已经设置的 Allow 报头。
+func NewProblem(w http.ResponseWriter, r *http.Request, status int) *Problem {
+	p := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: r.URL.Path,
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "" {
+		p.Allow = strings.Split(allow, ", ")
+	}
+
+	return p
+}
+
+// WriteProblem 向客户端输出 p 的内容。
+//
+// 根据 Accept 报头决定输出的格式，若客户端更倾向于 application/problem+json
+// 或是 application/json，则以 application/problem+json 的格式输出，
+// 否则采用与 http.Error 相同的纯文本格式输出 p.Title。
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if !acceptJSON(r.Header.Get("Accept")) {
+		http.Error(w, p.Title, p.Status)
+		return
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil { // 理论上不会出错
+		http.Error(w, p.Title, p.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(data)
+}
+
+// WriteParamError 以 400 状态码向客户端输出路由参数的错误信息。
+//
+// err 为 *params.ParamError 或是 params.BindError 时，
+// 各个参数的错误信息会保存在 Problem.InvalidParams 中；
+// 参数不存在时，Params 返回的也是 *params.ParamError，可以正常报告参数名称；
+// 其它类型的错误，包括直接传入的 params.ErrParamNotExists，
+// 仅将 err.Error() 作为 Problem.Detail 输出。
+// 输出格式可参考 WriteProblem。
+func WriteParamError(w http.ResponseWriter, r *http.Request, err error) {
+	p := NewProblem(w, r, http.StatusBadRequest)
+
+	switch e := err.(type) {
+	case *params.ParamError:
+		p.InvalidParams = []*InvalidParam{newInvalidParam(e.Name, e)}
+	case params.BindError:
+		p.InvalidParams = make([]*InvalidParam, 0, len(e))
+		for _, fe := range e {
+			p.InvalidParams = append(p.InvalidParams, newInvalidParam(fe.Param, fe.Err))
+		}
+	default: // 单独的 params.ErrParamNotExists 等无法确定参数名称的错误
+		p.Detail = err.Error()
+	}
+
+	WriteProblem(w, r, p)
+}
+
+func newInvalidParam(name string, err error) *InvalidParam {
+	pe, ok := err.(*params.ParamError)
+	if !ok {
+		return &InvalidParam{Name: name, Reason: err.Error()}
+	}
+
+	return &InvalidParam{
+		Name:   pe.Name,
+		Value:  pe.Value,
+		Type:   pe.Type,
+		Reason: pe.Err.Error(),
+	}
+}
+
+// ProblemHandler 生成一个输出状态码为 status 的错误信息的处理函数。
+//
+// New() 中 404 和 405 的默认处理方式，以及默认的 501 处理方式，均由此函数生成。
+// 输出格式可参考 WriteProblem。
+func ProblemHandler(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, NewProblem(w, r, status))
+	}
+}
+
+// 客户端是否更倾向于接收 JSON 格式的内容。
+//
+// 仅在 JSON 的权重高于纯文本时，才返回 true，权重相同时，以纯文本为准。
+func acceptJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	q := quality(accept, problemContentType)
+	if qq := quality(accept, jsonContentType); qq > q {
+		q = qq
+	}
+
+	return q > quality(accept, textContentType)
+}
+
+// 获取 Accept 报头中 typ 的权重值，若不存在，则返回 0。
+//
+// 当有多个值与 typ 匹配时，以最精确的那个为准，比如 text/plain 优先于 text/*，
+// text/* 又优先于 */*。
+func quality(accept, typ string) float64 {
+	var q float64
+	var specificity int // 当前 q 值对应的匹配精确度，值越大越精确
+	for _, item := range strings.Split(accept, ",") {
+		mimetype, qq := parseMediaRange(item)
+
+		s := specificityOf(mimetype, typ)
+		if s > specificity {
+			specificity = s
+			q = qq
+		}
+	}
+
+	return q
+}
+
+// 解析 Accept 报头中的单个值，返回其 mimetype 和 q 值。
+func parseMediaRange(item string) (string, float64) {
+	params := strings.Split(item, ";")
+	mimetype := strings.ToLower(strings.TrimSpace(params[0]))
+
+	q := 1.0
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+
+		val, err := strconv.ParseFloat(param[2:], 64)
+		if err != nil {
+			return mimetype, 0
+		}
+		q = val
+	}
+
+	return mimetype, q
+}
+
+// 获取 mimetype 与 typ 的匹配程度，0 表示不匹配。
+func specificityOf(mimetype, typ string) int {
+	switch {
+	case mimetype == typ:
+		return 3
+	case mimetype == "*/*":
+		return 1
+	case strings.HasSuffix(mimetype, "/*") &&
+		strings.HasPrefix(typ, mimetype[:len(mimetype)-1]):
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux/params"
+)
+
+func TestAcceptJSON(t *testing.T) {
+	a := assert.New(t)
+
+	a.False(acceptJSON(""))
+	a.False(acceptJSON("*/*"))
+	a.False(acceptJSON("text/plain"))
+	a.False(acceptJSON("text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"))
+	a.False(acceptJSON("application/json;q=0.5,text/*"))
+	a.False(acceptJSON("application/json;q=0.5,text/plain;q=0.5"))
+	a.False(acceptJSON("application/json;q=abc"))
+
+	a.True(acceptJSON("application/json"))
+	a.True(acceptJSON("application/problem+json"))
+	a.True(acceptJSON("Application/JSON"))
+	a.True(acceptJSON("application/*"))
+	a.True(acceptJSON("application/json, text/plain;q=0.5"))
+	a.True(acceptJSON("application/problem+json, application/json;q=0.1, */*;q=0.9"))
+	a.True(acceptJSON("text/plain;q=0, */*"))
+}
+
+func TestQuality(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(quality("text/plain", "application/json"), 0)
+	a.Equal(quality("text/plain;q=0.5", "text/plain"), 0.5)
+	a.Equal(quality("*/*;q=0.1,text/*;q=0.2,text/plain;q=0.3", "text/plain"), 0.3)
+	a.Equal(quality("*/*;q=0.1,text/*;q=0.2", "text/plain"), 0.2)
+	a.Equal(quality("*/*;q=0.1,text/*;q=0.2", "application/json"), 0.1)
+}
+
+func TestProblemHandler(t *testing.T) {
+	a := assert.New(t)
+
+	// 纯文本
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/not-exists", nil)
+	ProblemHandler(http.StatusNotFound).ServeHTTP(w, r)
+	a.Equal(w.Code, http.StatusNotFound).
+		Equal(w.Body.String(), http.StatusText(http.StatusNotFound)+"\n").
+		Equal(w.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+
+	// JSON
+	w = httptest.NewRecorder()
+	w.Header().Set("Allow", "GET, OPTIONS")
+	r = httptest.NewRequest(http.MethodPost, "/posts/1", nil)
+	r.Header.Set("Accept", "application/json")
+	ProblemHandler(http.StatusMethodNotAllowed).ServeHTTP(w, r)
+	a.Equal(w.Code, http.StatusMethodNotAllowed).
+		Equal(w.Header().Get("Content-Type"), problemContentType)
+
+	p := &Problem{}
+	a.NotError(json.Unmarshal(w.Body.Bytes(), p))
+	a.Equal(p, &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusMethodNotAllowed),
+		Status:   http.StatusMethodNotAllowed,
+		Instance: "/posts/1",
+		Allow:    []string{http.MethodGet, http.MethodOptions},
+	})
+}
+
+func TestMux_Problem(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+	srvmux.Get("/posts/1", buildHandler(http.StatusOK))
+
+	test := func(method, path string, status int, allow []string) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Accept", problemContentType)
+		srvmux.ServeHTTP(w, r)
+
+		a.Equal(w.Code, status).
+			Equal(w.Header().Get("Content-Type"), problemContentType)
+
+		p := &Problem{}
+		a.NotError(json.Unmarshal(w.Body.Bytes(), p))
+		a.Equal(p.Status, status).
+			Equal(p.Instance, path).
+			Equal(p.Allow, allow)
+	}
+
+	test(http.MethodGet, "/posts/2", http.StatusNotFound, nil)
+	test(http.MethodPost, "/posts/1", http.StatusMethodNotAllowed, []string{http.MethodGet, http.MethodOptions})
+	test("FOO", "/posts/1", http.StatusNotImplemented, nil)
+}
+
+func TestWriteParamError(t *testing.T) {
+	a := assert.New(t)
+
+	test := func(err error) *Problem {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/abc", nil)
+		r.Header.Set("Accept", "application/json")
+		WriteParamError(w, r, err)
+		a.Equal(w.Code, http.StatusBadRequest).
+			Equal(w.Header().Get("Content-Type"), problemContentType)
+
+		p := &Problem{}
+		a.NotError(json.Unmarshal(w.Body.Bytes(), p))
+		a.Equal(p.Status, http.StatusBadRequest).Equal(p.Instance, "/posts/abc")
+		return p
+	}
+
+	_, err := params.Params{"id": "abc"}.Int("id")
+	p := test(err)
+	a.Empty(p.Detail).Equal(p.InvalidParams, []*InvalidParam{
+		{Name: "id", Value: "abc", Type: "int64", Reason: `strconv.ParseInt: parsing "abc": invalid syntax`},
+	})
+
+	err = params.Params{"id": "abc"}.Bind(&struct {
+		ID   int    `param:"id"`
+		Name string `param:"name"`
+	}{})
+	p = test(err)
+	a.Equal(len(p.InvalidParams), 2).
+		Equal(p.InvalidParams[0].Value, "abc").
+		Equal(p.InvalidParams[0].Type, "int").
+		Equal(p.InvalidParams[1].Name, "name").
+		Equal(p.InvalidParams[1].Reason, params.ErrParamNotExists.Error())
+
+	_, err = params.Params{}.Int("id")
+	p = test(err)
+	a.Empty(p.Detail).Equal(p.InvalidParams, []*InvalidParam{
+		{Name: "id", Type: "int64", Reason: params.ErrParamNotExists.Error()},
+	})
+
+	p = test(params.ErrParamNotExists)
+	a.Equal(p.Detail, params.ErrParamNotExists.Error()).Empty(p.InvalidParams)
+}
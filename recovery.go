@@ -0,0 +1,76 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/issue9/mux/internal/handlers"
+)
+
+// Panic 表示 Mux.ServeHTTP 中捕获的 panic 信息
+type Panic struct {
+	Value interface{} // 传递给 panic 的值
+	Route *Route      // 与请求匹配的路由项，没有匹配时为 nil
+	Stack []byte      // 发生 panic 时的调用栈
+}
+
+// RecoveryHandler 处理 Mux.ServeHTTP 中捕获的 panic
+type RecoveryHandler func(w http.ResponseWriter, r *http.Request, p *Panic)
+
+func (p *Panic) Error() string {
+	if p.Route == nil {
+		return fmt.Sprintf("panic: %v", p.Value)
+	}
+	return fmt.Sprintf("路由项 %s 发生 panic: %v", p.Route.Pattern(), p.Value)
+}
+
+// DefaultRecoveryHandler 默认的 panic 处理方式。
+//
+// 通过标准库的 log 输出 panic 信息及调用栈，并向客户端输出 500，
+// 输出格式可参考 WriteProblem。
+func DefaultRecoveryHandler(w http.ResponseWriter, r *http.Request, p *Panic) {
+	log.Printf("%s\n%s", p.Error(), p.Stack)
+	WriteProblem(w, r, NewProblem(w, r, http.StatusInternalServerError))
+}
+
+// SetRecovery 指定 panic 的处理函数，为 nil 时表示不捕获 panic，这也是默认值。
+//
+// 指定之后，Mux.ServeHTTP 中发生的 panic 都会被捕获并交由 h 处理，
+// 一般可以直接使用 DefaultRecoveryHandler：
+//  m.SetRecovery(mux.DefaultRecoveryHandler)
+//
+// http.ErrAbortHandler 表示主动中断请求，不会被捕获，而是继续向上传递给 net/http。
+// 若处理函数在 panic 之前已经输出了报头，h 中再输出的状态码将无效。
+func (mux *Mux) SetRecovery(h RecoveryHandler) *Mux {
+	mux.recovery = h
+	return mux
+}
+
+// 捕获 panic 并交由 Mux.recovery 处理，hs 为与请求匹配的路由项。
+//
+// 只能通过 defer 调用。
+func (mux *Mux) recover(w http.ResponseWriter, r *http.Request, hs *handlers.Handlers) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	if v == http.ErrAbortHandler {
+		if sw, ok := w.(*statusWriter); ok {
+			sw.aborted = true
+		}
+		panic(v)
+	}
+
+	p := &Panic{Value: v, Stack: debug.Stack()}
+	if hs != nil {
+		p.Route = hs.Route()
+	}
+	mux.recovery(w, r, p)
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestMux_SetRecovery(t *testing.T) {
+	a := assert.New(t)
+
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		panic("not found")
+	}
+	srvmux := New(false, false, notFound, nil)
+	a.NotNil(srvmux)
+
+	srvmux.GetFunc("/posts/{id:\\d+}", func(w http.ResponseWriter, r *http.Request) {
+		panic("post")
+	}).GetFunc("/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	// 默认不捕获
+	a.Panic(func() {
+		srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	})
+
+	var p *Panic
+	srvmux.SetRecovery(func(w http.ResponseWriter, r *http.Request, pp *Panic) {
+		p = pp
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	a.Equal(w.Code, http.StatusInternalServerError).
+		NotNil(p).
+		Equal(p.Value, "post").
+		Equal(p.Route.Pattern(), "/posts/{id:\\d+}").
+		NotEmpty(p.Stack).
+		Equal(p.Error(), "路由项 /posts/{id:\\d+} 发生 panic: post")
+
+	// 没有匹配的路由项
+	p = nil
+	w = httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/not-exists", nil))
+	a.Equal(w.Code, http.StatusInternalServerError).
+		NotNil(p).
+		Nil(p.Route).
+		Equal(p.Error(), "panic: not found")
+
+	// http.ErrAbortHandler 不会被捕获
+	p = nil
+	a.Panic(func() {
+		srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+	a.Nil(p)
+
+	srvmux.SetRecovery(nil)
+	a.Panic(func() {
+		srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	})
+}
+
+func TestDefaultRecoveryHandler(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	srvmux := New(false, false, nil, nil).SetRecovery(DefaultRecoveryHandler)
+	srvmux.GetFunc("/posts/{id:\\d+}", func(w http.ResponseWriter, r *http.Request) {
+		panic("post")
+	})
+
+	w := httptest.NewRecorder()
+	srvmux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	a.Equal(w.Code, http.StatusInternalServerError)
+	a.True(strings.Contains(buf.String(), "路由项 /posts/{id:\\d+} 发生 panic: post"))
+	a.True(strings.Contains(buf.String(), "recovery_test.go"))
+}
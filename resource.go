@@ -4,7 +4,10 @@
 
 package mux
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // Resource 以资源地址为对象的路由配置。
 //  r, _ := srv.Resource("/api/users/{id}")
@@ -119,6 +122,67 @@ func (r *Resource) Clean() *Resource {
 	return r
 }
 
+// SetMethodNotAllowed 指定当前资源的 405 处理方式，为 nil 时表示使用上一级的处理方式。
+//
+// 资源的处理方式优先于 Prefix.SetMethodNotAllowed 和 New() 中指定的值。
+// 只能在添加了处理函数之后调用，否则会 panic ErrRouteNotExists；
+// 资源的所有请求方法都被删除之后，此设置也将一并失效。
+func (r *Resource) SetMethodNotAllowed(h http.HandlerFunc) *Resource {
+	hs, err := r.mux.find(r.pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	if h == nil {
+		hs.SetMethodNotAllowed(nil)
+	} else {
+		hs.SetMethodNotAllowed(h)
+	}
+	return r
+}
+
+// SetNotImplemented 指定当前资源的 501 处理方式，为 nil 时表示使用上一级的处理方式。
+//
+// 其它说明可参考 Resource.SetMethodNotAllowed。
+func (r *Resource) SetNotImplemented(h http.HandlerFunc) *Resource {
+	hs, err := r.mux.find(r.pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	if h == nil {
+		hs.SetNotImplemented(nil)
+	} else {
+		hs.SetNotImplemented(h)
+	}
+	return r
+}
+
+// SetTimeout 相当于 Mux.SetTimeout(pattern, timeout) 的简易写法。
+//
+// 与 Resource.SetMethodNotAllowed 相同，只能在添加了处理函数之后调用，否则会 panic。
+func (r *Resource) SetTimeout(timeout time.Duration) *Resource {
+	if err := r.mux.SetTimeout(r.pattern, timeout); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// SetMaxBodySize 相当于 Mux.SetMaxBodySize(pattern, size) 的简易写法。
+//
+// 与 Resource.SetMethodNotAllowed 相同，只能在添加了处理函数之后调用，否则会 panic。
+func (r *Resource) SetMaxBodySize(size int64) *Resource {
+	if err := r.mux.SetMaxBodySize(r.pattern, size); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// SetMeta 相当于 Mux.SetMeta(pattern, key, val, methods...) 的简易写法
+func (r *Resource) SetMeta(key string, val interface{}, methods ...string) error {
+	return r.mux.SetMeta(r.pattern, key, val, methods...)
+}
+
 // Name 为一条路由项命名。
 // URL 可以通过此属性来生成地址。
 func (r *Resource) Name(name string) error {
@@ -113,3 +113,38 @@ func TestResource_Name_URL(t *testing.T) {
 	url, err = res.Mux().URL("action", map[string]string{"id": "1", "action": "blog"})
 	a.NotError(err).Equal(url, "/api/blog/1")
 }
+
+func TestResource_ErrorHandlers(t *testing.T) {
+	a := assert.New(t)
+	test := newTester(a, false, false)
+
+	test.prefix("/api").SetMethodNotAllowed(buildFunc(http.StatusTeapot))
+	res := test.resource("/api/users/{id:\\d+}")
+	res.Get(buildHandler(http.StatusOK))
+	test.matchTrue(http.MethodPost, "/api/users/1", http.StatusTeapot)
+	test.matchTrue("FOO", "/api/users/1", http.StatusNotImplemented)
+
+	res.SetMethodNotAllowed(buildFunc(http.StatusConflict)).
+		SetNotImplemented(buildFunc(http.StatusBadGateway))
+	test.matchTrue(http.MethodPost, "/api/users/1", http.StatusConflict)
+	test.matchTrue("FOO", "/api/users/1", http.StatusBadGateway)
+	test.matchTrue(http.MethodGet, "/api/users/1", http.StatusOK)
+
+	// 取消
+	res.SetMethodNotAllowed(nil).SetNotImplemented(nil)
+	test.matchTrue(http.MethodPost, "/api/users/1", http.StatusTeapot)
+	test.matchTrue("FOO", "/api/users/1", http.StatusNotImplemented)
+
+	// 不存在的路由项，不会生成新的路由项
+	a.Panic(func() {
+		test.resource("/res").SetMethodNotAllowed(buildFunc(http.StatusTeapot))
+	})
+	a.Panic(func() {
+		test.resource("/res").SetNotImplemented(buildFunc(http.StatusTeapot))
+	})
+	test.matchTrue(http.MethodGet, "/res", http.StatusNotFound)
+
+	a.Panic(func() {
+		test.resource("/api/{id").SetMethodNotAllowed(nil)
+	})
+}
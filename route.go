@@ -0,0 +1,153 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/issue9/mux/internal/handlers"
+	"github.com/issue9/mux/params"
+)
+
+type contextKey int
+
+// 保存 *Route 的关键字
+const contextKeyRoute contextKey = 0
+
+// Route 表示路由项的描述信息，包含了完整的匹配模式、名称以及支持的请求方法。
+//
+// 可通过 CurrentRoute 获取与当前请求匹配的路由项，
+// 一般用于统计、日志等需要以路由项而不是实际路径作为标识的场景。
+type Route = handlers.Route
+
+// CurrentRoute 获取与当前请求匹配的路由项。
+//
+// 仅在 Mux.ServeHTTP 调用的处理函数中才有值，否则返回 nil。
+func CurrentRoute(r *http.Request) *Route {
+	if route, ok := r.Context().Value(contextKeyRoute).(*Route); ok {
+		return route
+	}
+
+	return nil
+}
+
+// Meta 获取与当前请求匹配的路由项中名为 key 的元数据。
+//
+// 会根据当前请求的请求方法查找，
+// 相当于 CurrentRoute(r).Meta(r.Method, key) 的简易写法。
+func Meta(r *http.Request, key string) (interface{}, bool) {
+	route := CurrentRoute(r)
+	if route == nil {
+		return nil, false
+	}
+
+	return route.Meta(r.Method, key)
+}
+
+// 保存路由项的 context.Context 实现。
+//
+// 路由参数仅保存在请求的 http.Request.PathValue 中，
+// 首次通过 params.Get 获取时，才根据路由项的参数名称从 req 中生成 params.Params，
+// 之后的获取操作均返回该实例。
+type routeContext struct {
+	context.Context
+	req   *http.Request
+	route *Route
+
+	once sync.Once
+	ps   params.Params
+}
+
+// 包含了 routeContext 的 http.Request。
+//
+// 请求和 context.Context 在同一块内存中，
+// 与没有路由参数时直接使用原来的请求相比，不会增加内存分配的次数。
+type routeRequest struct {
+	http.Request
+	ctx routeContext
+}
+
+// 生成包含路由参数和路由项的 *http.Request。
+//
+// 路由参数通过 http.Request.SetPathValue 写入，ps 在返回之后即可丢弃。
+func newRouteContext(r *http.Request, ps params.Params, route *Route) *http.Request {
+	rr := &routeRequest{
+		ctx: routeContext{
+			Context: r.Context(),
+			route:   route,
+		},
+	}
+	rr.ctx.req = &rr.Request
+
+	names := route.Params()
+	if sharePathValues(r, names) {
+		// 直接复制的请求与 r 共用保存路径参数的空间，SetPathValue 会修改 r 中的值，
+		// Clone 会复制一份独立的路径参数。
+		rr.Request = *r.Clone(&rr.ctx)
+	} else {
+		// WithContext 会被内联，其返回的副本不会逃逸，所以不会产生内存分配。
+		rr.Request = *r.WithContext(&rr.ctx)
+	}
+
+	for _, name := range names {
+		if val, found := ps[name]; found {
+			rr.Request.SetPathValue(name, val)
+		}
+	}
+
+	return &rr.Request
+}
+
+// r 中是否可能已经包含了需要被 names 覆盖的路径参数。
+//
+// 比如嵌套的 Mux，或是由 http.ServeMux 分发的请求，
+// 此时写入路径参数会修改到 r 本身的内容。
+func sharePathValues(r *http.Request, names []string) bool {
+	if len(names) == 0 {
+		return false
+	}
+
+	if r.Context().Value(contextKeyRoute) != nil {
+		return true
+	}
+
+	for _, name := range names {
+		if r.PathValue(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *routeContext) Value(key interface{}) interface{} {
+	switch key {
+	case contextKeyRoute:
+		return ctx.route
+	case params.ContextKeyParams:
+		if ps := ctx.params(); len(ps) > 0 { // 与 context.WithValue 的行为保持一致，没有参数时不保存
+			return ps
+		}
+	}
+
+	return ctx.Context.Value(key)
+}
+
+// 从 http.Request.PathValue 中生成路由参数，仅在首次调用时生成，之后返回同一对象。
+func (ctx *routeContext) params() params.Params {
+	ctx.once.Do(func() {
+		names := ctx.route.Params()
+		if len(names) == 0 {
+			return
+		}
+
+		ctx.ps = make(params.Params, len(names))
+		for _, name := range names {
+			ctx.ps[name] = ctx.req.PathValue(name)
+		}
+	})
+	return ctx.ps
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux/internal/handlers"
+	"github.com/issue9/mux/params"
+)
+
+func TestCurrentRoute(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	var route *Route
+	var ps params.Params
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route = CurrentRoute(r)
+		ps = Params(r)
+	})
+
+	test := func(method, path, pattern, name string, methods []string, p params.Params) {
+		route = nil
+		ps = nil
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		srvmux.ServeHTTP(w, r)
+
+		a.NotNil(route)
+		a.Equal(route.Pattern(), pattern).
+			Equal(route.Name(), name).
+			Equal(route.Methods(), methods).
+			Equal(ps, p)
+	}
+
+	// 先命名，后添加路由项
+	a.NotError(srvmux.Name("post", "/posts/{id:\\d+}"))
+	srvmux.Get("/posts/{id:\\d+}", h).
+		Post("/posts/{id:\\d+}", h).
+		Get("/posts", h)
+	test(http.MethodGet, "/posts/1", "/posts/{id:\\d+}", "post", []string{"GET", "OPTIONS", "POST"}, params.Params{"id": "1"})
+	test(http.MethodGet, "/posts", "/posts", "", []string{"GET", "OPTIONS"}, nil)
+
+	// 先添加路由项，后命名
+	a.NotError(srvmux.Prefix("/posts").Name("posts", ""))
+	test(http.MethodGet, "/posts", "/posts", "posts", []string{"GET", "OPTIONS"}, nil)
+
+	// 未经过 Mux.ServeHTTP
+	r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	a.Nil(CurrentRoute(r))
+}
+
+func TestRouteContext(t *testing.T) {
+	a := assert.New(t)
+
+	type key int
+	r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	r = r.WithContext(context.WithValue(r.Context(), key(1), 1))
+
+	// 没有参数
+	route := &Route{}
+	rr := newRouteContext(r, nil, route)
+	a.Nil(params.Get(rr))
+	a.Equal(CurrentRoute(rr), route)
+	a.Equal(rr.Context().Value(key(1)), 1)
+
+	// 有参数，仅保存路由项中声明的参数
+	hs := handlers.New(false)
+	hs.SetPattern("/posts/{id}", []string{"id"})
+	rr = newRouteContext(r, params.Params{"id": "1", "other": "2"}, hs.Route())
+	a.Equal(params.Get(rr), params.Params{"id": "1"})
+	a.Equal(rr.PathValue("id"), "1").Empty(rr.PathValue("other"))
+	a.Equal(rr.Context().Value(key(1)), 1)
+
+	// 多次获取返回同一实例
+	ps := params.Get(rr)
+	ps["id"] = "2"
+	a.Equal(params.Get(rr), params.Params{"id": "2"})
+	a.Equal(rr.PathValue("id"), "1")
+
+	// 嵌套时，外层的参数依然可以访问
+	rrr := newRouteContext(rr, nil, route)
+	a.Equal(params.Get(rrr), params.Params{"id": "2"})
+}
+
+// 嵌套的 Mux 不会修改外层请求中的路径参数
+func TestMux_PathValue_nested(t *testing.T) {
+	a := assert.New(t)
+
+	var innerID, innerPID string
+	inner := New(false, false, nil, nil)
+	inner.GetFunc("/{id}/1/posts/{pid}", func(w http.ResponseWriter, r *http.Request) {
+		innerID = r.PathValue("id")
+		innerPID = r.PathValue("pid")
+	})
+
+	var outerID, outerPID string
+	var ps params.Params
+	outer := New(false, false, nil, nil)
+	outer.GetFunc("/users/{id}/{sub:.+}", func(w http.ResponseWriter, r *http.Request) {
+		inner.ServeHTTP(w, r)
+		outerID = r.PathValue("id")
+		outerPID = r.PathValue("pid")
+		ps = Params(r)
+	})
+
+	w := httptest.NewRecorder()
+	outer.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/posts/2", nil))
+	a.Equal(w.Code, http.StatusOK)
+	a.Equal(innerID, "users").Equal(innerPID, "2")
+	a.Equal(outerID, "1").Empty(outerPID)
+	a.Equal(ps, params.Params{"id": "1", "sub": "posts/2"})
+}
+
+func TestMux_PathValue(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	var id, path string
+	var ps params.Params
+	srvmux.GetFunc("/posts/{id:\\d+}/{path}", func(w http.ResponseWriter, r *http.Request) {
+		id = r.PathValue("id")
+		path = r.PathValue("path")
+		ps = Params(r)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/posts/1/a/b", nil)
+	srvmux.ServeHTTP(w, r)
+	a.Equal(id, "1").
+		Equal(path, "a/b").
+		Equal(ps, params.Params{"id": "1", "path": "a/b"}) // 兼容原来的获取方式
+	a.Empty(r.PathValue("id")) // 不会修改原来的请求
+}
+
+func BenchmarkMux_ServeHTTP_Route(b *testing.B) {
+	srvmux := New(false, false, nil, nil)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CurrentRoute(r) == nil {
+			b.Error("CurrentRoute 返回 nil")
+		}
+	})
+	srvmux.Get("/posts", h)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		srvmux.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkMux_ServeHTTP_Params(b *testing.B) {
+	srvmux := New(false, false, nil, nil)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CurrentRoute(r) == nil || r.PathValue("id") != "1" {
+			b.Error("CurrentRoute 或 PathValue 的值不正确")
+		}
+	})
+	srvmux.Get("/posts/{id:\\d+}/{slug}", h)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/posts/1/abc", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		srvmux.ServeHTTP(w, r)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	a.NotNil(srvmux)
+
+	var owner, scope interface{}
+	var found bool
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		owner, _ = Meta(r, "owner")
+		scope, found = Meta(r, "scope")
+	})
+
+	test := func(method, path string, o, s interface{}, f bool) {
+		owner, scope, found = nil, nil, false
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		srvmux.ServeHTTP(w, r)
+
+		a.Equal(owner, o).Equal(scope, s).Equal(found, f)
+	}
+
+	// Mux，路由项不存在时返回错误，且不会生成新的路由项
+	err := srvmux.SetMeta("/posts", "owner", "team1")
+	a.True(errors.Is(err, ErrRouteNotExists))
+	a.Empty(srvmux.Routes())
+	srvmux.Get("/posts", h).Post("/posts", h)
+	a.NotError(srvmux.SetMeta("/posts", "owner", "team1"))
+	a.NotError(srvmux.SetMeta("/posts", "scope", "write", http.MethodPost))
+	a.Error(srvmux.SetMeta("/posts", "scope", "write", "FOO"))
+	a.Error(srvmux.SetMeta("/posts/{id:\\d+", "scope", "write"))
+	test(http.MethodGet, "/posts", "team1", nil, false)
+	test(http.MethodPost, "/posts", "team1", "write", true)
+
+	// Prefix
+	p := srvmux.Prefix("/admin")
+	p.Get("/users", h)
+	a.NotError(p.SetMeta("/users", "scope", "admin"))
+	test(http.MethodGet, "/admin/users", nil, "admin", true)
+
+	// Resource
+	res := srvmux.Resource("/posts/{id:\\d+}")
+	res.Get(h).Delete(h)
+	a.NotError(res.SetMeta("scope", "read"))
+	a.NotError(res.SetMeta("scope", "delete", http.MethodDelete))
+	test(http.MethodGet, "/posts/1", nil, "read", true)
+	test(http.MethodDelete, "/posts/1", nil, "delete", true)
+
+	// 路由项中可以获取所有的元数据
+	routes := srvmux.Routes()
+	a.Equal(routes[len(routes)-1].Pattern(), "/posts/{id:\\d+}")
+	a.Equal(routes[len(routes)-1].Metas(http.MethodDelete), map[string]interface{}{"scope": "delete"})
+
+	// 未经过 Mux.ServeHTTP
+	r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	val, found := Meta(r, "owner")
+	a.False(found).Nil(val)
+}
+
+func TestMeta_concurrent(t *testing.T) {
+	a := assert.New(t)
+	srvmux := New(false, false, nil, nil)
+	srvmux.GetFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
+		Meta(r, "owner")
+		CurrentRoute(r).Metas(r.Method)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			srvmux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts", nil))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		a.NotError(srvmux.SetMeta("/posts", "owner", i))
+		a.NotError(srvmux.SetMeta("/posts", "scope", i, http.MethodGet))
+	}
+	<-done
+}
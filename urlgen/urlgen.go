@@ -0,0 +1,188 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package urlgen 根据命名的路由项生成类型安全的地址构建函数。
+//
+// 比如名为 user-post 的路由项 /users/{uid:\d+}/posts/{slug}，会生成以下函数：
+//  // URLUserPost 生成路由项 user-post 的地址：/users/{uid:\d+}/posts/{slug}
+//  func URLUserPost(uid int64, slug string) string {
+//      return "/users/" + strconv.FormatInt(uid, 10) + "/posts/" + slug
+//  }
+// 正则表达式为 \d+ 或是 [0-9]+ 的参数类型为 int64，其它均为 string。
+// 与 Mux.URL 相同，参数值不会被转义，也不会验证是否符合正则表达式。
+//
+// 路由项可以来自配置文件，配合 go generate 使用：
+//  //go:generate muxurl -config=./routes.yaml -pkg=routes -o=./urls.go
+//
+// 也可以来自于 Mux 实例，在一个单独的生成程序中调用：
+//  m := mux.New(false, false, nil, nil)
+//  registerRoutes(m) // 注册路由的函数
+//  err := urlgen.Generate(w, "routes", urlgen.FromMux(m))
+package urlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/issue9/mux"
+	"github.com/issue9/mux/config"
+	"github.com/issue9/mux/internal/pattern"
+	"github.com/issue9/mux/internal/tree"
+)
+
+// Route 表示需要生成地址函数的路由项
+type Route struct {
+	Name    string
+	Pattern string
+}
+
+// FromMux 获取 m 中所有命名的路由项
+func FromMux(m *mux.Mux) []*Route {
+	names := m.Names()
+
+	routes := make([]*Route, 0, len(names))
+	for name, pattern := range names {
+		routes = append(routes, &Route{Name: name, Pattern: pattern})
+	}
+	return routes
+}
+
+// FromConfig 获取配置文件 path 中所有命名的路由项，文件格式可参考 config.ParseFile。
+func FromConfig(path string) ([]*Route, error) {
+	conf, err := config.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]*Route, 0, len(conf.Routes))
+	for _, r := range conf.Routes {
+		if r.Name != "" {
+			routes = append(routes, &Route{Name: r.Name, Pattern: r.Pattern})
+		}
+	}
+	return routes, nil
+}
+
+// Generate 生成包名为 pkg 的 Go 代码，包含 routes 中所有路由项的地址函数。
+//
+// 函数按名称排序，函数名为 URL 加上驼峰形式的路由项名称，若有重名的函数，则返回错误。
+func Generate(w io.Writer, pkg string, routes []*Route) error {
+	sorted := make([]*Route, len(routes))
+	copy(sorted, routes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	funcs := new(bytes.Buffer)
+	names := make(map[string]string, len(sorted))
+	var useStrconv bool
+	for _, r := range sorted {
+		fn := "URL" + pattern.ExportName(r.Name)
+		if name, found := names[fn]; found {
+			return fmt.Errorf("路由项 %s 和 %s 生成的函数名 %s 相同", name, r.Name, fn)
+		}
+		names[fn] = r.Name
+
+		used, err := writeFunc(funcs, fn, r)
+		if err != nil {
+			return fmt.Errorf("路由项 %s 出错：%s", r.Name, err.Error())
+		}
+		useStrconv = useStrconv || used
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by muxurl. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package", pkg)
+	if useStrconv {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, `import "strconv"`)
+	}
+	buf.Write(funcs.Bytes())
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// 输出单个路由项的函数，返回值表示是否用到了 strconv 包。
+func writeFunc(w io.Writer, fn string, r *Route) (bool, error) {
+	segs, err := tree.Parse(r.Pattern)
+	if err != nil {
+		return false, err
+	}
+
+	args := make([]string, 0, len(segs))
+	exprs := make([]string, 0, len(segs))
+	vars := make(map[string]bool, len(segs))
+	var useStrconv bool
+
+	for _, seg := range segs {
+		if !seg.IsParam {
+			exprs = append(exprs, strconv.Quote(seg.Value))
+			continue
+		}
+
+		v := varName(seg.Name, len(args))
+		for vars[v] {
+			v += "_"
+		}
+		vars[v] = true
+
+		if pattern.IsInt(seg.Regexp) {
+			args = append(args, v+" int64")
+			exprs = append(exprs, "strconv.FormatInt("+v+", 10)")
+			useStrconv = true
+		} else {
+			args = append(args, v+" string")
+			exprs = append(exprs, v)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "// %s 生成路由项 %s 的地址：%s\n", fn, r.Name, r.Pattern)
+	fmt.Fprintf(w, "func %s(%s) string {\n", fn, strings.Join(args, ", "))
+	fmt.Fprintf(w, "return %s\n", strings.Join(exprs, " + "))
+	fmt.Fprintln(w, "}")
+
+	return useStrconv, nil
+}
+
+// 将参数名称转换成小写开头的驼峰形式的变量名，index 为参数的索引，
+// 在无法转换时，用于生成 p0、p1 等变量名。
+func varName(name string, index int) string {
+	v := pattern.ExportName(name)
+	if v == "" {
+		return "p" + strconv.Itoa(index)
+	}
+
+	rs := []rune(v)
+	if unicode.IsDigit(rs[0]) {
+		return "p" + v
+	}
+
+	// 将开头连续的大写字母转换成小写，比如 ID 转换成 id，URLPath 转换成 urlPath。
+	for i := 0; i < len(rs) && unicode.IsUpper(rs[i]); i++ {
+		if i > 0 && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+			break
+		}
+		rs[i] = unicode.ToLower(rs[i])
+	}
+	v = string(rs)
+
+	if token.Lookup(v).IsKeyword() || v == "strconv" {
+		v += "_"
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package urlgen
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/mux"
+)
+
+func TestGenerate(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(Generate(buf, "routes", []*Route{
+		{Name: "user-post", Pattern: "/users/{uid:\\d+}/posts/{slug}"},
+		{Name: "home", Pattern: "/"},
+		{Name: "file", Pattern: "/files/{type}/{path}.{ext:[a-z]+}"},
+		{Name: "item", Pattern: "/items/{id:[0-9]+}/{_}"},
+	}))
+	a.Equal(buf.String(), `// Code generated by muxurl. DO NOT EDIT.
+
+package routes
+
+import "strconv"
+
+// URLFile 生成路由项 file 的地址：/files/{type}/{path}.{ext:[a-z]+}
+func URLFile(type_ string, path string, ext string) string {
+	return "/files/" + type_ + "/" + path + "." + ext
+}
+
+// URLHome 生成路由项 home 的地址：/
+func URLHome() string {
+	return "/"
+}
+
+// URLItem 生成路由项 item 的地址：/items/{id:[0-9]+}/{_}
+func URLItem(id int64, p1 string) string {
+	return "/items/" + strconv.FormatInt(id, 10) + "/" + p1
+}
+
+// URLUserPost 生成路由项 user-post 的地址：/users/{uid:\d+}/posts/{slug}
+func URLUserPost(uid int64, slug string) string {
+	return "/users/" + strconv.FormatInt(uid, 10) + "/posts/" + slug
+}
+`)
+
+	// 不需要 strconv
+	buf.Reset()
+	a.NotError(Generate(buf, "routes", []*Route{{Name: "post", Pattern: "/posts/{slug}"}}))
+	a.False(bytes.Contains(buf.Bytes(), []byte("strconv")))
+
+	// 函数名相同
+	a.Error(Generate(buf, "routes", []*Route{
+		{Name: "user-post", Pattern: "/users/posts"},
+		{Name: "user.post", Pattern: "/users/posts"},
+	}))
+
+	// 语法错误
+	a.Error(Generate(buf, "routes", []*Route{{Name: "post", Pattern: "/posts/{slug"}}))
+}
+
+func TestFromMux(t *testing.T) {
+	a := assert.New(t)
+	m := mux.New(false, false, nil, nil)
+	m.Get("/posts/{id:\\d+}", http.NotFoundHandler())
+	a.NotError(m.Name("post", "/posts/{id:\\d+}"))
+
+	routes := FromMux(m)
+	a.Equal(routes, []*Route{{Name: "post", Pattern: "/posts/{id:\\d+}"}})
+}
+
+func TestFromConfig(t *testing.T) {
+	a := assert.New(t)
+
+	routes, err := FromConfig("../config/testdata/routes.json")
+	a.NotError(err)
+	a.Equal(routes, []*Route{{Name: "post", Pattern: "/posts/{id:\\d+}"}})
+
+	routes, err = FromConfig("../config/testdata/routes.yaml")
+	a.NotError(err)
+	a.Equal(routes, []*Route{{Name: "post", Pattern: "/posts/{id:\\d+}"}})
+
+	routes, err = FromConfig("../config/testdata/not-exists.json")
+	a.Error(err).Nil(routes)
+}
+
+func TestVarName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(varName("user_id", 0), "userId")
+	a.Equal(varName("ID", 0), "id")
+	a.Equal(varName("URLPath", 0), "urlPath")
+	a.Equal(varName("Path", 0), "path")
+	a.Equal(varName("func", 0), "func_")
+	a.Equal(varName("strconv", 0), "strconv_")
+	a.Equal(varName("*", 2), "p2")
+	a.Equal(varName("1st", 0), "p1st")
+}